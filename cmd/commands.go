@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
+	"gitlab.melroy.org/melroy/fediresolve/resolver"
+)
+
+// The subcommands force the way the input is resolved, for when the heuristics of the
+// root command guess wrong (e.g. an URL with an @ in its path, or a domain that should be resolved as actor)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <url|handle>",
+	Short: "Resolve an URL, handle or domain, guessing what it is (the default)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runResolve(args[0], (*resolver.Resolver).ResolveRaw)
+	},
+}
+
+var actorCmd = &cobra.Command{
+	Use:   "actor <handle|url|domain>",
+	Short: "Resolve an actor, a domain resolves to the instance actor",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runResolve(args[0], (*resolver.Resolver).ResolveActor)
+	},
+}
+
+var objectCmd = &cobra.Command{
+	Use:   "object <url>",
+	Short: "Resolve an URL as ActivityPub object",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runResolve(args[0], (*resolver.Resolver).ResolveObject)
+	},
+}
+
+var nodeinfoCmd = &cobra.Command{
+	Use:   "nodeinfo <domain>",
+	Short: "Show the nodeinfo (software, version, usage) of an instance",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runResolve(args[0], (*resolver.Resolver).NodeInfo)
+	},
+}
+
+var webfingerCmd = &cobra.Command{
+	Use:   "webfinger <handle>",
+	Short: "Print the raw WebFinger response (JRD) of a handle",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rejectFlags(cmd, "format", "template", "template-file", "ics")
+		r := newResolver()
+		r.Log = os.Stderr
+		jrd, err := r.WebFinger(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		if len(queryFlag) > 0 {
+			if !printQueries(jrd, queryFlag) {
+				os.Exit(1)
+			}
+			return
+		}
+		pretty, err := formatter.FormatJSON(jrd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		fmt.Println(pretty)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd, actorCmd, objectCmd, nodeinfoCmd, webfingerCmd)
+}
+
+// runResolve resolves the input using the given resolve function, and prints the result
+func runResolve(input string, resolve func(r *resolver.Resolver, input string) ([]byte, error)) {
+	r := newResolver()
+	raw, err := resolve(r, input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", input, err)
+		os.Exit(1)
+	}
+	printResult(r, input, raw)
+}
+
+// rejectFlags exits with an error when one of the (output) flags is used, for subcommands that can't honour them
+func rejectFlags(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			fmt.Fprintf(os.Stderr, "The --%s option can't be used with the %s command\n", name, cmd.Name())
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint <url|handle>",
+	Short: "Check an ActivityPub object for common problems",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rejectFlags(cmd, "format", "query", "template", "template-file", "ics")
+		r := newResolver()
+		r.Log = os.Stderr
+		raw, err := r.ResolveRaw(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		problems := formatter.Lint(raw)
+		if len(problems) == 0 {
+			fmt.Println("No problems found")
+			return
+		}
+		fmt.Printf("Found %d problems:\n", len(problems))
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		os.Exit(1)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
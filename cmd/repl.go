@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/peterh/liner"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
+	"gitlab.melroy.org/melroy/fediresolve/resolver"
+)
+
+const replHelp = `Enter a Fediverse URL or handle to resolve it, or one of the commands:
+  <number>  Follow the link with this number in the last result
+  links     List the links in the last result
+  back      Go back to the previous result
+  raw       Toggle between the summary and the raw JSON
+  help      Show this help
+  quit      Exit (or press Ctrl+D)`
+
+// replPage is a resolved object in the history of the interactive shell
+type replPage struct {
+	input string
+	raw   []byte
+	links []formatter.Link
+}
+
+// repl is an interactive shell, to resolve and browse ActivityPub objects like a (terminal) web browser
+type repl struct {
+	resolver *resolver.Resolver
+	// pages is the navigation history, the last page is the current one
+	pages   []replPage
+	showRaw bool
+}
+
+// historyFile returns the path of the file the command history is stored in
+func historyFile() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "fediresolve", "history"), nil
+}
+
+// runREPL runs the interactive shell until the user quits
+func runREPL(r *resolver.Resolver) error {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+
+	// The history is kept across sessions, but failing to load or save it isn't fatal
+	historyPath, err := historyFile()
+	if err == nil {
+		if file, err := os.Open(historyPath); err == nil {
+			line.ReadHistory(file)
+			file.Close()
+		}
+		defer saveHistory(line, historyPath)
+	}
+
+	fmt.Printf("fediresolve %s, type \"help\" for the commands\n", Version)
+	shell := &repl{resolver: r}
+	for {
+		input, err := line.Prompt("fediresolve> ")
+		if errors.Is(err, io.EOF) || errors.Is(err, liner.ErrPromptAborted) {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading input: %v", err)
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+
+		if quit := shell.handle(input); quit {
+			return nil
+		}
+	}
+}
+
+// saveHistory writes the command history to the history file
+func saveHistory(line *liner.State, historyPath string) {
+	if err := os.MkdirAll(filepath.Dir(historyPath), 0700); err != nil {
+		return
+	}
+	if file, err := os.OpenFile(historyPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600); err == nil {
+		line.WriteHistory(file)
+		file.Close()
+	}
+}
+
+// handle handles a single line of input, returning true when the user wants to quit
+func (s *repl) handle(input string) bool {
+	switch strings.ToLower(input) {
+	case "quit", "exit", "q":
+		return true
+	case "help", "?":
+		fmt.Println(replHelp)
+	case "links", "l":
+		if page := s.current(); page != nil {
+			printLinks(page.links)
+		}
+	case "back", "b":
+		if len(s.pages) < 2 {
+			fmt.Println("Nothing to go back to")
+			return false
+		}
+		s.pages = s.pages[:len(s.pages)-1]
+		s.show()
+	case "raw", "r":
+		s.showRaw = !s.showRaw
+		s.show()
+	default:
+		if number, err := strconv.Atoi(input); err == nil {
+			page := s.current()
+			if page == nil || number < 1 || number > len(page.links) {
+				fmt.Printf("There is no link with number %d\n", number)
+				return false
+			}
+			input = page.links[number-1].URL
+		}
+		s.open(input)
+	}
+	return false
+}
+
+// current returns the current page, or nil if nothing has been resolved yet
+func (s *repl) current() *replPage {
+	if len(s.pages) == 0 {
+		return nil
+	}
+	return &s.pages[len(s.pages)-1]
+}
+
+// open resolves the URL or handle and shows it
+func (s *repl) open(input string) {
+	raw, err := s.resolver.ResolveRaw(input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", input, err)
+		return
+	}
+	s.pages = append(s.pages, replPage{input: input, raw: raw, links: formatter.Links(raw)})
+	s.show()
+}
+
+// show prints the current page, either as summary (followed by the numbered links) or as raw JSON
+func (s *repl) show() {
+	page := s.current()
+	if page == nil {
+		return
+	}
+	if s.showRaw {
+		pretty, err := formatter.FormatJSON(formatter.HideSensitiveContent(page.raw, s.resolver.FormatOptions))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", page.input, err)
+			return
+		}
+		fmt.Println(pretty)
+		return
+	}
+	fmt.Println(s.resolver.Summarize(page.raw))
+	printLinks(page.links)
+}
+
+// printLinks prints the numbered links, which can be followed by entering their number
+func printLinks(links []formatter.Link) {
+	if len(links) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println("Links:")
+	for i, link := range links {
+		fmt.Printf("  [%d] %s: %s\n", i+1, link.Label, link.URL)
+	}
+}
@@ -4,15 +4,44 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
 	"gitlab.melroy.org/melroy/fediresolve/resolver"
+	"golang.org/x/term"
 )
 
 const Version = "1.0"
 
-var versionFlag bool
+var (
+	versionFlag           bool
+	resolveRecipientsFlag bool
+	resolveMentionsFlag   bool
+	verifyLinksFlag       bool
+	followMovesFlag       bool
+	resolveCommunityFlag  bool
+	resolveQuotesFlag     bool
+	langFlag              []string
+	imagesFlag            bool
+	imageProtocolFlag     string
+	icsFlag               string
+	templateFlag          string
+	templateFileFlag      string
+	queryFlag             []string
+	formatFlag            string
+	maxContentFlag        string
+	colorFlag             string
+	themeFlag             string
+	tzFlag                string
+	showSensitiveFlag     bool
+	tuiFlag               bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "fediresolve [url|handle]",
@@ -20,18 +49,36 @@ var rootCmd = &cobra.Command{
 	Long: `Fediresolve is a CLI tool that resolves Fediverse URLs and handles.
 
 It can parse and display content from Mastodon, Mbin, Lemmy, and other Fediverse platforms.
-The tool supports both direct URLs to posts/comments/threads and Fediverse handles like @username@server.com.`,
+The tool supports both direct URLs to posts/comments/threads and Fediverse handles like @username@server.com.
+Use the subcommands to force how the input is resolved, when it's guessed wrong.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if versionFlag {
 			fmt.Println("fediresolve version", Version)
 			os.Exit(0)
 		}
-		var input string
+		r := newResolver()
 
-		if len(args) > 0 {
+		// Without arguments, start the interactive shell (or read a single line when stdin is piped)
+		var input string
+		if tuiFlag {
+			if len(args) > 0 {
+				input = args[0]
+			}
+			if err := runTUI(r, input); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		} else if len(args) > 0 {
 			input = args[0]
+		} else if term.IsTerminal(int(os.Stdin.Fd())) {
+			if err := runREPL(r); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
 		} else {
-			fmt.Print("Enter a Fediverse URL or handle: ")
 			reader := bufio.NewReader(os.Stdin)
 			input, _ = reader.ReadString('\n')
 			input = strings.TrimSpace(input)
@@ -42,19 +89,212 @@ The tool supports both direct URLs to posts/comments/threads and Fediverse handl
 			return
 		}
 
-		r := resolver.NewResolver()
-		result, err := r.Resolve(input)
+		raw, err := r.ResolveRaw(input)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", input, err)
 			os.Exit(1)
 		}
 
-		fmt.Println(result)
+		printResult(r, input, raw)
 	},
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&versionFlag, "version", false, "Print the version number and exit")
+	rootCmd.PersistentFlags().BoolVar(&resolveRecipientsFlag, "resolve-recipients", false, "Dereference the to/cc recipients to show who they are (e.g. followers of @user@domain.tld)")
+	rootCmd.PersistentFlags().BoolVar(&resolveMentionsFlag, "resolve-mentions", false, "Dereference mentioned actors to show their display names")
+	rootCmd.PersistentFlags().BoolVar(&verifyLinksFlag, "verify-links", false, "Verify the links in profile fields by checking for a rel=\"me\" link back to the actor")
+	rootCmd.PersistentFlags().BoolVar(&followMovesFlag, "follow-moves", false, "Follow the movedTo chain of migrated accounts and show the migration history")
+	rootCmd.PersistentFlags().BoolVar(&resolveCommunityFlag, "resolve-community", false, "Dereference the moderators, featured posts and outbox of communities (groups)")
+	rootCmd.PersistentFlags().BoolVar(&resolveQuotesFlag, "resolve-quotes", false, "Fetch quoted posts and show them inline")
+	rootCmd.PersistentFlags().BoolVar(&showSensitiveFlag, "show-sensitive", false, "Show content hidden behind a content warning, and sensitive images")
+	// The browser and the interactive shell only exist without a subcommand
+	rootCmd.Flags().BoolVar(&tuiFlag, "tui", false, "Explore the object (and the objects it links to) in a full-screen browser")
+	rootCmd.PersistentFlags().BoolVar(&imagesFlag, "images", false, "Download and show avatars and image attachments in the terminal")
+	rootCmd.PersistentFlags().StringVar(&imageProtocolFlag, "image-protocol", formatter.ImageProtocolAuto, "Protocol used to show images: auto, ansi, kitty, iterm2 or sixel")
+	rootCmd.PersistentFlags().StringVar(&icsFlag, "ics", "", "Export the resolved event to an iCalendar (.ics) file")
+	rootCmd.PersistentFlags().StringVar(&templateFlag, "template", "", "Go template used for the output instead of the summary (e.g. '{{.Type}} {{.Author}} {{.Published}}')")
+	rootCmd.PersistentFlags().StringVar(&templateFileFlag, "template-file", "", "File containing the Go template used for the output instead of the summary")
+	rootCmd.PersistentFlags().StringVar(&maxContentFlag, "max-content", strconv.Itoa(formatter.DefaultMaxContent), "Maximum length of the content in characters, or \"unlimited\"")
+	rootCmd.PersistentFlags().StringVar(&colorFlag, "color", "auto", "When to use colors: auto, always or never (auto respects NO_COLOR)")
+	rootCmd.PersistentFlags().StringVar(&themeFlag, "theme", "default", "Color theme: default, high-contrast, monochrome or the path to a theme file")
+	rootCmd.PersistentFlags().StringVar(&tzFlag, "tz", "Local", "Timezone used to show dates (e.g. UTC or Europe/Amsterdam)")
+	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", formatter.OutputFormatText, "Output format: text, markdown or html")
+	rootCmd.PersistentFlags().StringArrayVar(&queryFlag, "query", nil, "Print the value at the gjson path instead of the summary (e.g. publicKey.publicKeyPem), can be repeated")
+	rootCmd.PersistentFlags().StringSliceVar(&langFlag, "lang", languagesFromEnv(), "Preferred languages for multilingual content, in order of preference (e.g. nl,en)")
+}
+
+// newResolver creates the resolver, configured using the (validated) flags
+func newResolver() *resolver.Resolver {
+	switch imageProtocolFlag {
+	case formatter.ImageProtocolAuto, formatter.ImageProtocolANSI, formatter.ImageProtocolKitty, formatter.ImageProtocolITerm2, formatter.ImageProtocolSixel:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown image protocol: %s\n", imageProtocolFlag)
+		os.Exit(1)
+	}
+
+	switch formatFlag {
+	case formatter.OutputFormatText, formatter.OutputFormatMarkdown, formatter.OutputFormatHTML:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown output format: %s\n", formatFlag)
+		os.Exit(1)
+	}
+	// NO_COLOR and the terminal detection are handled by the color package (auto)
+	switch colorFlag {
+	case "auto":
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown color mode: %s\n", colorFlag)
+		os.Exit(1)
+	}
+	theme, err := loadTheme(themeFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading theme: %v\n", err)
+		os.Exit(1)
+	}
+
+	timezone, err := time.LoadLocation(tzFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unknown timezone: %s\n", tzFlag)
+		os.Exit(1)
+	}
+
+	maxContent, err := parseMaxContent(maxContentFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --max-content: %v\n", err)
+		os.Exit(1)
+	}
+	if templateFlag != "" && templateFileFlag != "" {
+		fmt.Fprintln(os.Stderr, "Use either --template or --template-file, not both")
+		os.Exit(1)
+	}
+	if templateFileFlag != "" {
+		content, err := os.ReadFile(templateFileFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading template file: %v\n", err)
+			os.Exit(1)
+		}
+		templateFlag = string(content)
+	}
+
+	r := resolver.NewResolver()
+	r.FormatOptions.ResolveRecipients = resolveRecipientsFlag
+	r.FormatOptions.ResolveMentions = resolveMentionsFlag
+	r.FormatOptions.VerifyLinks = verifyLinksFlag
+	r.FormatOptions.FollowMoves = followMovesFlag
+	r.FormatOptions.ResolveCommunity = resolveCommunityFlag
+	r.FormatOptions.ResolveQuotes = resolveQuotesFlag
+	r.FormatOptions.Languages = langFlag
+	r.FormatOptions.ShowSensitive = showSensitiveFlag
+	r.FormatOptions.MaxContent = maxContent
+	r.FormatOptions.Theme = &theme
+	r.FormatOptions.Timezone = timezone
+	r.FormatOptions.Images = imagesFlag
+	r.FormatOptions.ImageProtocol = imageProtocolFlag
+	// Keep stdout clean when the output is meant to be processed further
+	if len(queryFlag) > 0 || templateFlag != "" || formatFlag != formatter.OutputFormatText {
+		r.Log = os.Stderr
+	}
+	return r
+}
+
+// printResult prints the resolved object, as summary or in the format asked for using the flags
+func printResult(r *resolver.Resolver, input string, raw []byte) {
+	if len(queryFlag) > 0 {
+		if !printQueries(raw, queryFlag) {
+			os.Exit(1)
+		}
+	} else if templateFlag != "" {
+		output, err := formatter.ExecuteTemplate(templateFlag, raw, r.FormatOptions)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", input, err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+		if !strings.HasSuffix(output, "\n") {
+			fmt.Println()
+		}
+	} else if formatFlag != formatter.OutputFormatText {
+		output, err := r.Export(raw, formatFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", input, err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+	} else {
+		result, err := r.FormatResult(raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error formatting %s: %v\n", input, err)
+			os.Exit(1)
+		}
+		fmt.Println(result)
+	}
+
+	if icsFlag != "" {
+		ics, err := formatter.EventToICS(raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting %s to iCalendar: %v\n", input, err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(icsFlag, []byte(ics), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", icsFlag, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Event exported to: %s\n", icsFlag)
+	}
+}
+
+// loadTheme returns the built-in theme with the given name, or loads the theme file
+func loadTheme(nameOrPath string) (formatter.Theme, error) {
+	if theme, ok := formatter.BuiltinTheme(nameOrPath); ok {
+		return theme, nil
+	}
+	return formatter.LoadTheme(nameOrPath)
+}
+
+// parseMaxContent parses the maximum content length, which is a positive number or "unlimited"
+func parseMaxContent(value string) (int, error) {
+	if value == "unlimited" {
+		return -1, nil
+	}
+	maxContent, err := strconv.Atoi(value)
+	if err != nil || maxContent <= 0 {
+		return 0, fmt.Errorf("expected a positive number or \"unlimited\", got %q", value)
+	}
+	return maxContent, nil
+}
+
+// printQueries prints the values at the gjson paths, one per line.
+// Returns false if one of the paths doesn't exist in the object.
+func printQueries(raw []byte, paths []string) bool {
+	found := true
+	for _, path := range paths {
+		value := gjson.GetBytes(raw, path)
+		if !value.Exists() {
+			fmt.Fprintf(os.Stderr, "No value found for query: %s\n", path)
+			found = false
+			continue
+		}
+		fmt.Println(value.String())
+	}
+	return found
+}
+
+// languagesFromEnv returns the preferred language based on the LANG environment variable,
+// e.g. nl_NL.UTF-8 becomes nl-NL
+func languagesFromEnv() []string {
+	lang := os.Getenv("LANG")
+	// Strip the encoding and modifier
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	if lang == "" || lang == "C" || lang == "POSIX" {
+		return nil
+	}
+	return []string{strings.ReplaceAll(lang, "_", "-")}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
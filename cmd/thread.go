@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
+	"gitlab.melroy.org/melroy/fediresolve/resolver"
+)
+
+// maxThreadDepth is the maximum number of parent posts that are followed
+const maxThreadDepth = 20
+
+var threadCmd = &cobra.Command{
+	Use:   "thread <url>",
+	Short: "Show a post with all the posts it replies to, oldest first",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rejectFlags(cmd, "ics")
+		r := newResolver()
+		r.Log = os.Stderr
+		raw, err := r.ResolveRaw(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+		raw, err = threadPost(r, raw)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		posts := append(threadParents(r, raw), raw)
+		switch {
+		case len(queryFlag) > 0 || templateFlag != "":
+			for _, post := range posts {
+				printResult(r, gjson.GetBytes(post, "id").String(), post)
+			}
+		case formatFlag != formatter.OutputFormatText:
+			output, err := r.ExportThread(posts, formatFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error exporting %s: %v\n", args[0], err)
+				os.Exit(1)
+			}
+			fmt.Print(output)
+		default:
+			for i, post := range posts {
+				if i > 0 {
+					fmt.Println(strings.Repeat("─", 40))
+				}
+				fmt.Printf("Post %d of %d\n", i+1, len(posts))
+				fmt.Println(r.Summarize(post))
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(threadCmd)
+}
+
+// threadPost returns the post of the thread: activities (like Create or Announce) are unwrapped to their object
+func threadPost(r *resolver.Resolver, raw []byte) ([]byte, error) {
+	object := gjson.GetBytes(raw, "object")
+	switch {
+	case gjson.GetBytes(raw, "inReplyTo").Exists() || !object.Exists():
+		return raw, nil
+	case object.IsObject():
+		return []byte(object.Raw), nil
+	}
+	return r.ResolveObject(object.String())
+}
+
+// threadParents follows the inReplyTo chain of the post, returning the parents oldest first.
+// Parents that can't be resolved (e.g. deleted or private posts) end the chain.
+func threadParents(r *resolver.Resolver, raw []byte) [][]byte {
+	var parents [][]byte
+	seen := map[string]bool{gjson.GetBytes(raw, "id").String(): true}
+	for len(parents) < maxThreadDepth {
+		inReplyTo := gjson.GetBytes(raw, "inReplyTo")
+		if inReplyTo.IsObject() {
+			inReplyTo = inReplyTo.Get("id")
+		}
+		parentURL := inReplyTo.String()
+		if parentURL == "" || seen[parentURL] {
+			break
+		}
+		seen[parentURL] = true
+
+		parent, err := r.ResolveObject(parentURL)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving parent %s: %v\n", parentURL, err)
+			break
+		}
+		parents = append([][]byte{parent}, parents...)
+		raw = parent
+	}
+	return parents
+}
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
+	"gitlab.melroy.org/melroy/fediresolve/resolver"
+)
+
+// maxPrefetch is the maximum number of links of an object that are prefetched in the background
+const maxPrefetch = 10
+
+// prefetchLabels are the labels of the links that are prefetched: the objects a reader most likely follows.
+// Collections (like followers and the outbox) and the instance are only fetched when they are opened.
+var prefetchLabels = map[string]bool{
+	"Author":      true,
+	"Actor":       true,
+	"Object":      true,
+	"Target":      true,
+	"In Reply To": true,
+	"Quote Of":    true,
+}
+
+const tuiHelp = "[::b]Tab[::-] switch pane  [::b]Enter[::-] open link / fold JSON  [::b]Backspace[::-] back  [::b]q[::-] quit"
+
+// browser is a full-screen explorer for ActivityPub objects, with panes for the summary,
+// the (foldable) raw JSON and the links in the object
+type browser struct {
+	resolver *resolver.Resolver
+	app      *tview.Application
+
+	input   *tview.InputField
+	summary *tview.TextView
+	json    *tview.TreeView
+	links   *tview.List
+	status  *tview.TextView
+	panes   []tview.Primitive
+
+	// pages is the navigation history, the last page is the current one
+	pages []browserPage
+
+	// cache contains the prefetched objects by URL
+	cacheMu sync.Mutex
+	cache   map[string][]byte
+}
+
+// browserPage is a resolved object in the history of the browser
+type browserPage struct {
+	replPage
+	summary string
+}
+
+// runTUI runs the full-screen browser, starting with the input (if any)
+func runTUI(r *resolver.Resolver, input string) error {
+	// The summary is shown in a pane, images and progress messages would mess up the screen
+	r.FormatOptions.Images = false
+	r.Log = io.Discard
+	// Colors are translated to the colors of the pane
+	color.NoColor = false
+
+	b := &browser{
+		resolver: r,
+		app:      tview.NewApplication(),
+		input:    tview.NewInputField().SetLabel("URL or handle: "),
+		summary:  tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true),
+		json:     tview.NewTreeView(),
+		links:    tview.NewList().ShowSecondaryText(false),
+		status:   tview.NewTextView().SetDynamicColors(true).SetText(tuiHelp),
+		cache:    make(map[string][]byte),
+	}
+	b.summary.SetBorder(true).SetTitle(" Summary ")
+	b.json.SetBorder(true).SetTitle(" JSON ")
+	b.links.SetBorder(true).SetTitle(" Links ")
+	b.panes = []tview.Primitive{b.input, b.summary, b.links, b.json}
+
+	b.input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter && b.input.GetText() != "" {
+			b.open(b.input.GetText())
+		}
+	})
+	b.json.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.links, 0, 1, false).
+		AddItem(b.json, 0, 2, false)
+	main := tview.NewFlex().
+		AddItem(b.summary, 0, 3, false).
+		AddItem(right, 0, 2, false)
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.input, 1, 0, true).
+		AddItem(main, 0, 1, false).
+		AddItem(b.status, 1, 0, false)
+
+	b.app.SetInputCapture(b.handleKey)
+	if input != "" {
+		b.open(input)
+	}
+	return b.app.SetRoot(layout, true).EnableMouse(true).Run()
+}
+
+// handleKey handles the global key bindings, keys are passed on to the input field when it has focus
+func (b *browser) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyTab, tcell.KeyBacktab:
+		b.cycleFocus(event.Key() == tcell.KeyBacktab)
+		return nil
+	case tcell.KeyCtrlC:
+		b.app.Stop()
+		return nil
+	}
+	if b.app.GetFocus() == b.input {
+		return event
+	}
+	switch {
+	case event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2:
+		b.back()
+		return nil
+	case event.Rune() == 'q':
+		b.app.Stop()
+		return nil
+	case event.Rune() == '/':
+		b.app.SetFocus(b.input)
+		return nil
+	}
+	return event
+}
+
+// cycleFocus moves the focus to the next (or previous) pane
+func (b *browser) cycleFocus(reverse bool) {
+	focus := b.app.GetFocus()
+	for i, pane := range b.panes {
+		if pane == focus {
+			next := i + 1
+			if reverse {
+				next = i - 1 + len(b.panes)
+			}
+			b.app.SetFocus(b.panes[next%len(b.panes)])
+			return
+		}
+	}
+	b.app.SetFocus(b.panes[0])
+}
+
+// open resolves the URL or handle in the background (or takes it from the prefetch cache) and shows it
+func (b *browser) open(input string) {
+	b.status.SetText(fmt.Sprintf("Resolving %s...", tview.Escape(input)))
+
+	// Format the summary for the width of its pane
+	summaryResolver := *b.resolver
+	if _, _, width, _ := b.summary.GetInnerRect(); width > 20 {
+		summaryResolver.FormatOptions.Width = width
+	}
+	go func() {
+		b.cacheMu.Lock()
+		raw, cached := b.cache[input]
+		b.cacheMu.Unlock()
+		if !cached {
+			var err error
+			raw, err = b.resolver.ResolveRaw(input)
+			if err != nil {
+				b.app.QueueUpdateDraw(func() {
+					b.status.SetText(fmt.Sprintf("[red]Error resolving %s: %s", tview.Escape(input), tview.Escape(err.Error())))
+				})
+				return
+			}
+		}
+
+		// Formatting might dereference other objects as well, so it's done in the background too
+		page := browserPage{
+			replPage: replPage{input: input, raw: raw, links: browserLinks(raw)},
+			summary:  summaryResolver.Summarize(raw),
+		}
+		b.app.QueueUpdateDraw(func() {
+			b.pages = append(b.pages, page)
+			b.show()
+		})
+		b.prefetch(page.links)
+	}()
+}
+
+// back shows the previous page
+func (b *browser) back() {
+	if len(b.pages) < 2 {
+		return
+	}
+	b.pages = b.pages[:len(b.pages)-1]
+	b.show()
+}
+
+// show shows the current page in the panes
+func (b *browser) show() {
+	page := b.pages[len(b.pages)-1]
+	b.input.SetText(page.input)
+	b.status.SetText(tuiHelp)
+	b.summary.SetText(tview.TranslateANSI(tview.Escape(page.summary))).ScrollToBeginning()
+
+	// Content behind a content warning is hidden in the JSON as well
+	root := jsonNode("(root)", gjson.ParseBytes(formatter.HideSensitiveContent(page.raw, b.resolver.FormatOptions)))
+	b.json.SetRoot(root).SetCurrentNode(root)
+
+	b.links.Clear()
+	for _, link := range page.links {
+		url := link.URL
+		b.links.AddItem(tview.Escape(fmt.Sprintf("%s: %s", link.Label, url)), "", 0, func() {
+			b.open(url)
+		})
+	}
+}
+
+// prefetch resolves the (object) links in the background, so following them is instant
+func (b *browser) prefetch(links []formatter.Link) {
+	var objectLinks []formatter.Link
+	for _, link := range links {
+		if prefetchLabels[link.Label] && len(objectLinks) < maxPrefetch {
+			objectLinks = append(objectLinks, link)
+		}
+	}
+	for _, link := range objectLinks {
+		go func(url string) {
+			b.cacheMu.Lock()
+			_, cached := b.cache[url]
+			b.cacheMu.Unlock()
+			if cached {
+				return
+			}
+			if raw, err := b.resolver.ResolveRaw(url); err == nil {
+				b.cacheMu.Lock()
+				b.cache[url] = raw
+				b.cacheMu.Unlock()
+			}
+		}(link.URL)
+	}
+}
+
+// browserLinks returns the links of the object, including the instance it lives on (which shows its nodeinfo)
+func browserLinks(raw []byte) []formatter.Link {
+	links := formatter.Links(raw)
+	if id, err := url.Parse(gjson.GetBytes(raw, "id").String()); err == nil && id.Host != "" {
+		links = append(links, formatter.Link{Label: "Instance", URL: id.Scheme + "://" + id.Host})
+	}
+	return links
+}
+
+// jsonNode creates the (foldable) tree of a JSON value, objects and arrays are collapsed except for the root
+func jsonNode(key string, value gjson.Result) *tview.TreeNode {
+	if !value.IsObject() && !value.IsArray() {
+		return tview.NewTreeNode(tview.Escape(fmt.Sprintf("%s: %s", key, value.Raw)))
+	}
+
+	children := 0
+	node := tview.NewTreeNode("")
+	value.ForEach(func(childKey, child gjson.Result) bool {
+		name := childKey.String()
+		if value.IsArray() {
+			name = fmt.Sprintf("[%d]", children)
+		}
+		node.AddChild(jsonNode(name, child))
+		children++
+		return true
+	})
+	brackets := "{%d}"
+	if value.IsArray() {
+		brackets = "[%d]"
+	}
+	node.SetText(tview.Escape(fmt.Sprintf("%s "+brackets, key, children))).SetColor(tcell.ColorYellow)
+	return node.SetExpanded(key == "(root)")
+}
@@ -0,0 +1,123 @@
+package formatter
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/buckket/go-blurhash"
+	fatihColor "github.com/fatih/color"
+	"github.com/tidwall/gjson"
+)
+
+// Width of the blurhash color preview in terminal columns
+const blurhashPreviewColumns = 16
+
+// formatAttachments formats the attachments (images, videos, links, etc.) of an object of the given type,
+// followed by an accessibility audit of the media attachments. Sensitive media (of a sensitive object,
+// or marked sensitive itself) is only shown as blurhash, unless showing sensitive content is enabled.
+func formatAttachments(attachments []gjson.Result, objectType string, sensitive bool, parts []string, opts Options, bold, green, red func(a ...interface{}) string) []string {
+	if len(attachments) == 0 {
+		return parts
+	}
+
+	var media, missingAltText int
+	parts = append(parts, fmt.Sprintf("%s:", bold("Attachments")))
+	for i, attachment := range attachments {
+		attachmentType := attachment.Get("type").String()
+		mediaType := attachment.Get("mediaType").String()
+		url := attachment.Get("url").String()
+		href := attachment.Get("href").String()
+		name := attachment.Get("name").String()
+
+		// Truncate long names
+		name = truncate(name, 100)
+
+		attachmentInfo := fmt.Sprintf("  %d. %s", i+1, green(attachmentType))
+		if mediaType != "" {
+			attachmentInfo += fmt.Sprintf(" (%s)", mediaType)
+		}
+		if name != "" {
+			attachmentInfo += fmt.Sprintf(": %s", name)
+		}
+		parts = append(parts, attachmentInfo)
+
+		// For type Page and attachment type Link, show href if present
+		if objectType == "Page" && attachmentType == "Link" && href != "" {
+			parts = append(parts, fmt.Sprintf("     URL: %s", green(href)))
+		} else if url != "" {
+			parts = append(parts, fmt.Sprintf("     URL: %s", green(url)))
+		}
+
+		width := attachment.Get("width").Int()
+		height := attachment.Get("height").Int()
+		if width > 0 && height > 0 {
+			parts = append(parts, fmt.Sprintf("     Size: %dx%d", width, height))
+		}
+
+		if focalPoint := attachment.Get("focalPoint").Array(); len(focalPoint) == 2 {
+			parts = append(parts, fmt.Sprintf("     Focal Point: %.2f, %.2f", focalPoint[0].Float(), focalPoint[1].Float()))
+		}
+
+		if isVisualMedia(attachment) {
+			media++
+			if strings.TrimSpace(attachment.Get("name").String()) == "" {
+				missingAltText++
+				parts = append(parts, fmt.Sprintf("     %s", red("Missing alt text!")))
+			}
+		}
+
+		attachmentSensitive := sensitive || attachment.Get("sensitive").Bool()
+		if attachmentSensitive {
+			parts = append(parts, fmt.Sprintf("     %s", red("Sensitive media")))
+		}
+
+		hash := attachment.Get("blurhash").String()
+		if opts.Images && isImage(attachment) && (!attachmentSensitive || opts.ShowSensitive) {
+			if image := renderImage(url, hash, attachmentColumns, opts); image != "" {
+				parts = append(parts, image)
+			}
+		} else if preview := blurhashPreview(hash, opts); preview != "" {
+			parts = append(parts, fmt.Sprintf("     Preview: %s", preview))
+		}
+	}
+
+	if media > 0 {
+		if missingAltText > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Alt Text"), red(fmt.Sprintf("%d of %d images/videos are missing alt text", missingAltText, media))))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Alt Text"), green(fmt.Sprintf("all %d images/videos have alt text", media))))
+		}
+	}
+
+	return parts
+}
+
+// isVisualMedia returns true if the attachment is an image or a video, which should have alt text
+func isVisualMedia(attachment gjson.Result) bool {
+	return isImage(attachment) || attachment.Get("type").String() == "Video" ||
+		strings.HasPrefix(attachment.Get("mediaType").String(), "video/")
+}
+
+// blurhashPreview decodes the blurhash into a single line of colored blocks,
+// giving an impression of the colors of the media. Nothing is returned when colors are disabled,
+// or when the theme doesn't use colors (monochrome).
+func blurhashPreview(hash string, opts Options) string {
+	if hash == "" || fatihColor.NoColor || !opts.theme().usesColors() {
+		return ""
+	}
+	img, err := blurhash.Decode(hash, blurhashPreviewColumns, 2, 1)
+	if err != nil {
+		return ""
+	}
+	// A single terminal row holds two pixels: the background is the upper pixel,
+	// the lower half block is the lower pixel
+	var b strings.Builder
+	for x := 0; x < blurhashPreviewColumns; x++ {
+		upper := color.RGBAModel.Convert(img.At(x, 0)).(color.RGBA)
+		lower := color.RGBAModel.Convert(img.At(x, 1)).(color.RGBA)
+		fmt.Fprintf(&b, "\x1b[48;2;%d;%d;%dm\x1b[38;2;%d;%d;%dm\u2584", upper.R, upper.G, upper.B, lower.R, lower.G, lower.B)
+	}
+	b.WriteString("\x1b[0m")
+	return b.String()
+}
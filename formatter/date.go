@@ -0,0 +1,104 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout used to show dates
+const dateLayout = "Jan 02, 2006 15:04:05"
+
+// dateLayouts are the layouts dates are parsed with. ActivityStreams requires RFC 3339,
+// but software also uses dates without timezone (which are assumed to be UTC) and RFC 1123.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	"2006-01-02",
+}
+
+// parseDate parses a date in any of the supported layouts
+func parseDate(date string) (time.Time, bool) {
+	date = strings.TrimSpace(date)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// formatDate formats a date in the configured timezone, followed by the relative time (e.g. 3h ago).
+// Dates that can't be parsed are returned as-is.
+func formatDate(date string, opts Options) string {
+	t, ok := parseDate(date)
+	if !ok {
+		return date
+	}
+	return fmt.Sprintf("%s (%s)", t.In(opts.timezone()).Format(dateLayout), relativeTime(t, time.Now()))
+}
+
+// formatAbsoluteDate is like formatDate, without the relative time. It's used for exports, which
+// are read later on (when the relative time would be wrong).
+func formatAbsoluteDate(date string, opts Options) string {
+	t, ok := parseDate(date)
+	if !ok {
+		return date
+	}
+	return t.In(opts.timezone()).Format(dateLayout + " MST")
+}
+
+// formatUpdated formats the updated date, adding how long after publishing the object was edited.
+// An empty string is returned when the object hasn't been edited.
+func formatUpdated(published, updated string, opts Options) string {
+	if updated == "" || updated == published {
+		return ""
+	}
+	formatted := formatDate(updated, opts)
+	publishedTime, ok := parseDate(published)
+	updatedTime, ok2 := parseDate(updated)
+	if !ok || !ok2 {
+		return formatted
+	}
+	delta := updatedTime.Sub(publishedTime)
+	if delta <= 0 {
+		return formatted
+	}
+	return fmt.Sprintf("%s, edited %s after publishing", formatted, formatTimeDelta(delta))
+}
+
+// relativeTime describes the time relative to now, e.g. "3h ago" or "in 2d"
+func relativeTime(t, now time.Time) string {
+	delta := now.Sub(t)
+	if delta < 0 {
+		return "in " + formatTimeDelta(-delta)
+	}
+	if delta < time.Minute {
+		return "just now"
+	}
+	return formatTimeDelta(delta) + " ago"
+}
+
+// formatTimeDelta formats a duration using the largest unit that fits, e.g. 3h, 2d or 5mo
+func formatTimeDelta(delta time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case delta < time.Minute:
+		return fmt.Sprintf("%ds", int(delta.Seconds()))
+	case delta < time.Hour:
+		return fmt.Sprintf("%dm", int(delta.Minutes()))
+	case delta < day:
+		return fmt.Sprintf("%dh", int(delta.Hours()))
+	case delta < 30*day:
+		return fmt.Sprintf("%dd", int(delta/day))
+	case delta < 365*day:
+		return fmt.Sprintf("%dmo", int(delta/(30*day)))
+	}
+	return fmt.Sprintf("%dy", int(delta/(365*day)))
+}
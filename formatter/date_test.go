@@ -0,0 +1,111 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		date string
+		want time.Time
+		ok   bool
+	}{
+		{"2025-01-02T03:04:05Z", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"2025-01-02T03:04:05.123+02:00", time.Date(2025, 1, 2, 1, 4, 5, 123000000, time.UTC), true},
+		{"2025-01-02T03:04:05.123456", time.Date(2025, 1, 2, 3, 4, 5, 123456000, time.UTC), true},
+		{"2025-01-02T03:04:05", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"2025-01-02 03:04:05", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"Thu, 02 Jan 2025 03:04:05 +0100", time.Date(2025, 1, 2, 2, 4, 5, 0, time.UTC), true},
+		{"Thu, 02 Jan 2025 03:04:05 UTC", time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), true},
+		{"2025-01-02", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{" 2025-01-02 ", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"yesterday", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseDate(test.date)
+		if ok != test.ok || !got.Equal(test.want) {
+			t.Errorf("parseDate(%q) = %v, %v, want %v, %v", test.date, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{now, "just now"},
+		{now.Add(-30 * time.Second), "just now"},
+		{now.Add(-5 * time.Minute), "5m ago"},
+		{now.Add(-3 * time.Hour), "3h ago"},
+		{now.Add(-49 * time.Hour), "2d ago"},
+		{now.Add(-65 * 24 * time.Hour), "2mo ago"},
+		{now.Add(-800 * 24 * time.Hour), "2y ago"},
+		{now.Add(90 * time.Minute), "in 1h"},
+		{now.Add(10 * time.Second), "in 10s"},
+	}
+	for _, test := range tests {
+		if got := relativeTime(test.t, now); got != test.want {
+			t.Errorf("relativeTime(%v) = %q, want %q", test.t, got, test.want)
+		}
+	}
+}
+
+func TestFormatUpdated(t *testing.T) {
+	opts := Options{Timezone: time.UTC}
+	tests := []struct {
+		name      string
+		published string
+		updated   string
+		prefix    string
+		suffix    string
+	}{
+		{"not updated", "2025-01-02T03:04:05Z", "", "", ""},
+		{"equal", "2025-01-02T03:04:05Z", "2025-01-02T03:04:05Z", "", ""},
+		{"edited", "2025-01-02T03:04:05Z", "2025-01-02T05:34:05Z", "Jan 02, 2025 05:34:05 (", "), edited 2h after publishing"},
+		{"edited days later", "2025-01-02T03:04:05Z", "2025-01-05T03:04:05Z", "Jan 05, 2025 03:04:05 (", "), edited 3d after publishing"},
+		{"updated before published", "2025-01-02T03:04:05Z", "2025-01-01T03:04:05Z", "Jan 01, 2025 03:04:05 (", " ago)"},
+		{"invalid published", "unknown", "2025-01-02T03:04:05Z", "Jan 02, 2025 03:04:05 (", " ago)"},
+		{"invalid updated", "2025-01-02T03:04:05Z", "unknown", "unknown", "unknown"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := formatUpdated(test.published, test.updated, opts)
+			if !strings.HasPrefix(got, test.prefix) || !strings.HasSuffix(got, test.suffix) || (test.prefix == "" && got != "") {
+				t.Errorf("formatUpdated(%q, %q) = %q, want %q...%q", test.published, test.updated, got, test.prefix, test.suffix)
+			}
+		})
+	}
+}
+
+func TestFormatAbsoluteDateTimezone(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	if err != nil {
+		t.Fatalf("error loading timezone: %v", err)
+	}
+	tests := []struct {
+		name     string
+		timezone *time.Location
+		date     string
+		want     string
+	}{
+		{"UTC", time.UTC, "2025-07-01T12:00:00Z", "Jul 01, 2025 12:00:00 UTC"},
+		{"summer time", amsterdam, "2025-07-01T12:00:00Z", "Jul 01, 2025 14:00:00 CEST"},
+		{"winter time", amsterdam, "2025-01-01T12:00:00Z", "Jan 01, 2025 13:00:00 CET"},
+		{"offset in the date", time.UTC, "2025-01-01T12:00:00+05:30", "Jan 01, 2025 06:30:00 UTC"},
+		{"fixed zone", time.FixedZone("X", -3*60*60), "2025-01-01T01:00:00Z", "Dec 31, 2024 22:00:00 X"},
+		{"invalid date", time.UTC, "someday", "someday"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := formatAbsoluteDate(test.date, Options{Timezone: test.timezone}); got != test.want {
+				t.Errorf("formatAbsoluteDate(%q) = %q, want %q", test.date, got, test.want)
+			}
+		})
+	}
+}
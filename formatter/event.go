@@ -0,0 +1,187 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// formatPlace formats a location, which is normally a Place object (with a PostalAddress) but might be a plain string
+func formatPlace(location gjson.Result, parts []string, bold, green func(a ...interface{}) string) []string {
+	for _, place := range resultArray(location) {
+		if !place.IsObject() {
+			if place.String() != "" {
+				parts = append(parts, fmt.Sprintf("%s: %s", bold("Location"), place.String()))
+			}
+			continue
+		}
+
+		name := place.Get("name").String()
+		if name == "" {
+			name = place.Get("type").String()
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Location"), name))
+		if address := formatAddress(place.Get("address")); address != "" {
+			parts = append(parts, fmt.Sprintf("  Address: %s", address))
+		}
+		if latitude, longitude := place.Get("latitude"), place.Get("longitude"); latitude.Exists() && longitude.Exists() {
+			parts = append(parts, fmt.Sprintf("  Coordinates: %.6f, %.6f", latitude.Float(), longitude.Float()))
+		}
+		if url := firstString(place.Get("url")); url != "" {
+			parts = append(parts, fmt.Sprintf("  URL: %s", green(url)))
+		}
+	}
+	return parts
+}
+
+// formatAddress formats a PostalAddress object (or plain string) on a single line
+func formatAddress(address gjson.Result) string {
+	if !address.IsObject() {
+		return address.String()
+	}
+	var lines []string
+	for _, path := range []string{"streetAddress", "postalCode", "addressLocality", "addressRegion", "addressCountry"} {
+		if value := strings.TrimSpace(address.Get(path).String()); value != "" {
+			lines = append(lines, value)
+		}
+	}
+	return strings.Join(lines, ", ")
+}
+
+// formatParticipation formats the participation details of Mobilizon and Gancio events
+func formatParticipation(jsonStr []byte, parts []string, bold, green, red func(a ...interface{}) string) []string {
+	if joinMode := gjson.GetBytes(jsonStr, "joinMode").String(); joinMode != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Join Mode"), joinMode))
+	}
+
+	if participants := gjson.GetBytes(jsonStr, "participantCount"); participants.Exists() {
+		participantsStr := fmt.Sprintf("%d", participants.Int())
+		if capacity := gjson.GetBytes(jsonStr, "maximumAttendeeCapacity").Int(); capacity > 0 {
+			participantsStr += fmt.Sprintf(" of %d", capacity)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Participants"), participantsStr))
+	}
+
+	if remaining := gjson.GetBytes(jsonStr, "remainingAttendeeCapacity"); remaining.Exists() {
+		parts = append(parts, fmt.Sprintf("%s: %d", bold("Remaining Places"), remaining.Int()))
+	}
+
+	if anonymous := gjson.GetBytes(jsonStr, "anonymousParticipationEnabled"); anonymous.Exists() {
+		value := red("false")
+		if anonymous.Bool() {
+			value = green("true")
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Anonymous Participation"), value))
+	}
+
+	if external := gjson.GetBytes(jsonStr, "externalParticipationUrl").String(); external != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("External Participation"), green(external)))
+	}
+
+	return parts
+}
+
+// EventToICS exports an Event object as iCalendar (.ics) document
+func EventToICS(jsonData []byte) (string, error) {
+	// Events are often resolved as the activity that created them (e.g. Create by Mobilizon)
+	if object := gjson.GetBytes(jsonData, "object"); object.IsObject() {
+		jsonData = []byte(object.Raw)
+	}
+	if objectType := gjson.GetBytes(jsonData, "type").String(); objectType != "Event" {
+		return "", fmt.Errorf("object is not an Event but %s", objectType)
+	}
+
+	startTime, err := parseICSTime(gjson.GetBytes(jsonData, "startTime").String())
+	if err != nil {
+		return "", fmt.Errorf("invalid start time: %v", err)
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//FediResolve//EN",
+		"BEGIN:VEVENT",
+		"UID:" + gjson.GetBytes(jsonData, "id").String(),
+		"DTSTAMP:" + time.Now().UTC().Format("20060102T150405Z"),
+		"DTSTART:" + startTime,
+	}
+	if endTime, err := parseICSTime(gjson.GetBytes(jsonData, "endTime").String()); err == nil {
+		lines = append(lines, "DTEND:"+endTime)
+	}
+	if name := gjson.GetBytes(jsonData, "name").String(); name != "" {
+		lines = append(lines, "SUMMARY:"+escapeICSText(name))
+	}
+	if content := gjson.GetBytes(jsonData, "content").String(); content != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICSText(htmlToText(content)))
+	}
+
+	if place := resultArray(gjson.GetBytes(jsonData, "location")); len(place) > 0 {
+		location := place[0].String()
+		if place[0].IsObject() {
+			var locationParts []string
+			if name := place[0].Get("name").String(); name != "" {
+				locationParts = append(locationParts, name)
+			}
+			if address := formatAddress(place[0].Get("address")); address != "" {
+				locationParts = append(locationParts, address)
+			}
+			location = strings.Join(locationParts, ", ")
+			if latitude, longitude := place[0].Get("latitude"), place[0].Get("longitude"); latitude.Exists() && longitude.Exists() {
+				lines = append(lines, fmt.Sprintf("GEO:%f;%f", latitude.Float(), longitude.Float()))
+			}
+		}
+		if location != "" {
+			lines = append(lines, "LOCATION:"+escapeICSText(location))
+		}
+	}
+
+	url := firstString(gjson.GetBytes(jsonData, "url"))
+	if url == "" {
+		url = gjson.GetBytes(jsonData, "id").String()
+	}
+	if url != "" {
+		lines = append(lines, "URL:"+url)
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String(), nil
+}
+
+// parseICSTime converts an ISO 8601 date to the UTC date-time format of iCalendar
+func parseICSTime(isoDate string) (string, error) {
+	t, ok := parseDate(isoDate)
+	if !ok {
+		return "", fmt.Errorf("unsupported date: %q", isoDate)
+	}
+	return t.UTC().Format("20060102T150405Z"), nil
+}
+
+// escapeICSText escapes a text value for iCalendar
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(text)
+}
+
+// foldICSLine folds lines longer than 75 octets, as required by iCalendar,
+// taking care not to split multibyte characters
+func foldICSLine(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > 75 {
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	return b.String()
+}
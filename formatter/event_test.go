@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Meetup", "Meetup"},
+		{"Amsterdam, NL", `Amsterdam\, NL`},
+		{"a;b", `a\;b`},
+		{`C:\path`, `C:\\path`},
+		{"line 1\nline 2", `line 1\nline 2`},
+		{"line 1\r\nline 2", `line 1\nline 2`},
+		{`\,`, `\\\,`},
+	}
+	for _, test := range tests {
+		if got := escapeICSText(test.text); got != test.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Meetup"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("a", 200)},
+		{"long multibyte", "DESCRIPTION:" + strings.Repeat("é€😀", 40)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			folded := foldICSLine(test.line)
+			lines := strings.Split(folded, "\r\n")
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets long: %q", i, len(line), line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a multibyte character: %q", i, line)
+				}
+			}
+			// Unfolding must give the original line back
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != test.line {
+				t.Errorf("unfolded line = %q, want %q", unfolded, test.line)
+			}
+		})
+	}
+}
+
+func TestEventToICS(t *testing.T) {
+	event := `{"type":"Event","id":"https://m.example/events/1","name":"Meetup, again","startTime":"2025-05-01T18:00:00+02:00","endTime":"2025-05-01T20:00:00+02:00"}`
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{"event", event, false},
+		{"create activity", `{"type":"Create","object":` + event + `}`, false},
+		{"note", `{"type":"Note"}`, true},
+		{"missing start time", `{"type":"Event","id":"https://m.example/events/2"}`, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ics, err := EventToICS([]byte(test.json))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("EventToICS() = %q, want an error", ics)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EventToICS() returned error: %v", err)
+			}
+			for _, want := range []string{"UID:https://m.example/events/1\r\n", "DTSTART:20250501T160000Z\r\n", "DTEND:20250501T180000Z\r\n", "SUMMARY:Meetup\\, again\r\n"} {
+				if !strings.Contains(ics, want) {
+					t.Errorf("EventToICS() = %q, want it to contain %q", ics, want)
+				}
+			}
+		})
+	}
+}
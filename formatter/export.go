@@ -0,0 +1,372 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Output formats, besides the (default) text summary, the object can be exported as markdown or HTML document
+const (
+	OutputFormatText     = "text"
+	OutputFormatMarkdown = "markdown"
+	OutputFormatHTML     = "html"
+)
+
+// exportDocument is the data of an exported (markdown or HTML) document
+type exportDocument struct {
+	Title string
+	// Author is the handle of the author (or actor), AuthorURL links to its profile
+	Author    string
+	AuthorURL string
+	Avatar    string
+	Content   string
+	// ContentWarning is shown above the content, which is hidden unless showing sensitive content is enabled
+	ContentWarning string
+	Attachments    []SummaryAttachment
+	// HideMedia is true when the media is sensitive (or behind the content warning) and showing sensitive
+	// content isn't enabled, the attachments are only linked then instead of embedded
+	HideMedia bool
+	Metadata  [][2]string
+	JSON      string
+}
+
+// newExportDocument collects the data of the exported document, the author is dereferenced (if possible)
+// to show its handle and avatar
+func newExportDocument(jsonData []byte, opts Options) (exportDocument, error) {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, HideSensitiveContent(jsonData, opts), "", "  "); err != nil {
+		return exportDocument{}, fmt.Errorf("error parsing JSON: %v", err)
+	}
+
+	// The content of a Create (or Announce) is in its object
+	activity := gjson.ParseBytes(jsonData)
+	object, unwrapped := exportObject(jsonData, opts)
+	summary := NewSummary(object, opts)
+	doc := exportDocument{
+		Title:          summary.Name,
+		Content:        sanitizeHTML(summary.Content),
+		ContentWarning: summary.ContentWarning,
+		Attachments:    summary.Attachments,
+		HideMedia:      (summary.Sensitive || summary.ContentWarning != "") && !opts.ShowSensitive,
+		JSON:           strings.TrimSpace(pretty.String()),
+	}
+	if doc.ContentWarning != "" && !opts.ShowSensitive && doc.Content != "" {
+		doc.Content = "<p><em>" + html.EscapeString(hiddenContent) + "</em></p>"
+	}
+
+	if summary.Author != "" {
+		doc.Author = summary.Author
+		doc.AuthorURL = summary.Author
+		if actor := opts.fetch(summary.Author); actor != nil {
+			doc.Author = actorHandle(actor, summary.Author)
+			doc.Avatar = imageURL(gjson.GetBytes(actor, "icon"))
+		}
+	} else if summary.Username != "" {
+		// The object is an actor itself
+		doc.Avatar = summary.Icon
+		doc.Content = sanitizeHTML(summary.Summary)
+		if doc.Title == "" {
+			doc.Title = summary.Username
+		}
+	}
+	if doc.Title == "" {
+		doc.Title = summary.Type
+		if doc.Author != "" {
+			doc.Title += " by " + doc.Author
+		}
+	}
+
+	var activityType, activityID string
+	if unwrapped {
+		activityType = strings.Join(resultStrings(activity.Get("type")), ", ")
+		activityID = activity.Get("id").String()
+	}
+	for _, row := range [][2]string{
+		{"Activity", activityType},
+		{"Activity ID", activityID},
+		{"Type", strings.Join(summary.Types, ", ")},
+		{"ID", summary.ID},
+		{"URL", summary.URL},
+		{"Author", summary.Author},
+		{"Published", formatAbsoluteDate(summary.Published, opts)},
+		{"Updated", formatAbsoluteDate(summary.Updated, opts)},
+		{"Language", summary.Language},
+	} {
+		if row[1] != "" {
+			doc.Metadata = append(doc.Metadata, row)
+		}
+	}
+	if summary.Sensitive {
+		doc.Metadata = append(doc.Metadata, [2]string{"Sensitive", "true"})
+	}
+	return doc, nil
+}
+
+// exportedActivities are the activity types of which the object is exported, instead of the activity itself
+var exportedActivities = map[string]bool{"Create": true, "Update": true, "Announce": true}
+
+// exportObject returns the object that's exported: the object of a Create, Update or Announce activity
+// (dereferenced when it's a link, if possible), otherwise the object itself. True is returned when the
+// object of an activity is returned.
+func exportObject(jsonData []byte, opts Options) ([]byte, bool) {
+	if !exportedActivities[gjson.GetBytes(jsonData, "type").String()] {
+		return jsonData, false
+	}
+	object := gjson.GetBytes(jsonData, "object")
+	if object.IsObject() {
+		return []byte(object.Raw), true
+	}
+	if fetched := opts.fetch(object.String()); fetched != nil {
+		return fetched, true
+	}
+	return jsonData, false
+}
+
+// FormatMarkdown exports the ActivityPub object as a markdown document, with the raw JSON in a collapsible block
+func FormatMarkdown(jsonData []byte, opts Options) (string, error) {
+	doc, err := newExportDocument(jsonData, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	writeMarkdownDocument(&b, doc, "#")
+	return b.String(), nil
+}
+
+// FormatMarkdownThread exports the posts of a thread (oldest first) as a single markdown document
+func FormatMarkdownThread(posts [][]byte, opts Options) (string, error) {
+	docs, err := newExportDocuments(posts, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(singleLine(threadTitle(docs))))
+	for i, doc := range docs {
+		if i > 0 {
+			b.WriteString("\n---\n\n")
+		}
+		writeMarkdownDocument(&b, doc, "##")
+	}
+	return b.String(), nil
+}
+
+// writeMarkdownDocument writes the exported document as markdown, using the heading for its title
+// (sections get a level deeper). Remote data is escaped, so it can't break out of the markdown or inject HTML.
+func writeMarkdownDocument(b *strings.Builder, doc exportDocument, heading string) {
+	fmt.Fprintf(b, "%s %s\n\n", heading, escapeMarkdown(singleLine(doc.Title)))
+	if isSafeURL(doc.Avatar) {
+		fmt.Fprintf(b, "<img src=\"%s\" alt=\"Avatar\" width=\"64\">\n\n", template.HTMLEscapeString(doc.Avatar))
+	}
+	if doc.Author != "" {
+		fmt.Fprintf(b, "**Author:** %s\n\n", markdownLink(doc.Author, doc.AuthorURL))
+	}
+	if doc.ContentWarning != "" {
+		fmt.Fprintf(b, "**Content warning:** %s\n\n", escapeMarkdown(doc.ContentWarning))
+	}
+	if content := strings.TrimSpace(htmlToMarkdown(doc.Content)); content != "" {
+		b.WriteString(content + "\n\n")
+	}
+
+	if len(doc.Attachments) > 0 {
+		fmt.Fprintf(b, "%s# Attachments\n\n", heading)
+		for _, attachment := range doc.Attachments {
+			name := attachmentLabel(attachment)
+			if doc.HideMedia {
+				fmt.Fprintf(b, "- %s\n\n", markdownLink(name+" (sensitive)", attachment.URL))
+			} else if attachmentKind(attachment) == "image" && isSafeURL(attachment.URL) {
+				fmt.Fprintf(b, "![%s](%s)\n\n", escapeMarkdown(name), markdownURL(attachment.URL))
+			} else {
+				fmt.Fprintf(b, "- %s\n\n", markdownLink(name, attachment.URL))
+			}
+		}
+	}
+
+	fmt.Fprintf(b, "%s# Metadata\n\n| Property | Value |\n| --- | --- |\n", heading)
+	for _, row := range doc.Metadata {
+		fmt.Fprintf(b, "| %s | %s |\n", row[0], escapeMarkdown(singleLine(row[1])))
+	}
+
+	fence := "```"
+	for strings.Contains(doc.JSON, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "\n<details>\n<summary>Raw JSON</summary>\n\n%sjson\n%s\n%s\n\n</details>\n", fence, doc.JSON, fence)
+}
+
+// markdownEscaper escapes the characters that have a meaning in markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "{", `\{`, "}", `\}`, "[", `\[`, "]", `\]`,
+	"(", `\(`, ")", `\)`, "#", `\#`, "+", `\+`, "-", `\-`, ".", `\.`, "!", `\!`, "|", `\|`, "~", `\~`,
+)
+
+// escapeMarkdown escapes text for markdown, HTML is escaped as well since most markdown renderers allow it
+func escapeMarkdown(text string) string {
+	return template.HTMLEscapeString(markdownEscaper.Replace(text))
+}
+
+// markdownURL escapes the characters that would end the URL of a markdown link or image
+func markdownURL(rawURL string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(rawURL)
+}
+
+// markdownLink returns a markdown link, or only the (escaped) text when the URL isn't a http(s) URL
+func markdownLink(text, rawURL string) string {
+	if !isSafeURL(rawURL) {
+		return escapeMarkdown(text)
+	}
+	return fmt.Sprintf("[%s](%s)", escapeMarkdown(text), markdownURL(rawURL))
+}
+
+// newExportDocuments collects the data of the exported documents of all posts
+func newExportDocuments(posts [][]byte, opts Options) ([]exportDocument, error) {
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("thread has no posts")
+	}
+	docs := make([]exportDocument, 0, len(posts))
+	for _, post := range posts {
+		doc, err := newExportDocument(post, opts)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// threadTitle returns the title of an exported thread, named after its first post
+func threadTitle(docs []exportDocument) string {
+	return "Thread: " + docs[0].Title
+}
+
+// htmlTemplate is the template of exported HTML documents
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; line-height: 1.5; }
+.author { display: flex; align-items: center; gap: 0.5em; }
+.author img { width: 48px; height: 48px; border-radius: 8px; }
+.attachments img, .attachments video { max-width: 100%; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+article + article { border-top: 1px solid #ccc; margin-top: 2em; }
+pre { background: #f4f4f4; padding: 1em; overflow-x: auto; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Posts}}<article>
+{{if $.Thread}}<h2>{{.Title}}</h2>
+{{end}}{{if or .Author .Avatar}}<p class="author">{{if .Avatar}}<img src="{{.Avatar}}" alt="Avatar">{{end}}{{if .Author}}<a href="{{.AuthorURL}}">{{.Author}}</a>{{end}}</p>
+{{end}}{{if .ContentWarning}}<p class="content-warning"><strong>Content warning:</strong> {{.ContentWarning}}</p>
+{{end}}{{if .Content}}<div class="content">
+{{.Content}}
+</div>
+{{end}}{{if .Attachments}}{{if $.Thread}}<h3>Attachments</h3>{{else}}<h2>Attachments</h2>{{end}}
+<div class="attachments">
+{{range .Attachments}}{{if eq .Kind "image"}}<p><img src="{{.URL}}" alt="{{.Name}}"></p>
+{{else if eq .Kind "video"}}<p><video controls src="{{.URL}}" title="{{.Name}}"></video></p>
+{{else if eq .Kind "audio"}}<p><audio controls src="{{.URL}}" title="{{.Name}}"></audio></p>
+{{else}}<p><a href="{{.URL}}">{{.Label}}</a></p>
+{{end}}{{end}}</div>
+{{end}}{{if $.Thread}}<h3>Metadata</h3>{{else}}<h2>Metadata</h2>{{end}}
+<table>
+{{range .Metadata}}<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{end}}</table>
+<details>
+<summary>Raw JSON</summary>
+<pre><code>{{.JSON}}</code></pre>
+</details>
+</article>
+{{end}}</body>
+</html>
+`))
+
+// htmlAttachment is an attachment in the HTML template
+type htmlAttachment struct {
+	SummaryAttachment
+	Kind  string
+	Label string
+}
+
+// htmlPost is an exported document in the HTML template
+type htmlPost struct {
+	exportDocument
+	Content     template.HTML
+	Attachments []htmlAttachment
+}
+
+// FormatHTML exports the ActivityPub object as a self-contained HTML document, with sanitized content
+func FormatHTML(jsonData []byte, opts Options) (string, error) {
+	doc, err := newExportDocument(jsonData, opts)
+	if err != nil {
+		return "", err
+	}
+	return executeHTMLTemplate(doc.Title, []exportDocument{doc}, false)
+}
+
+// FormatHTMLThread exports the posts of a thread (oldest first) as a single HTML document
+func FormatHTMLThread(posts [][]byte, opts Options) (string, error) {
+	docs, err := newExportDocuments(posts, opts)
+	if err != nil {
+		return "", err
+	}
+	return executeHTMLTemplate(threadTitle(docs), docs, true)
+}
+
+// executeHTMLTemplate renders the documents using the HTML template
+func executeHTMLTemplate(title string, docs []exportDocument, thread bool) (string, error) {
+	var posts []htmlPost
+	for _, doc := range docs {
+		var attachments []htmlAttachment
+		for _, attachment := range doc.Attachments {
+			if doc.HideMedia {
+				// Sensitive media is linked instead of embedded
+				attachments = append(attachments, htmlAttachment{attachment, "", attachmentLabel(attachment) + " (sensitive)"})
+			} else {
+				attachments = append(attachments, htmlAttachment{attachment, attachmentKind(attachment), attachmentLabel(attachment)})
+			}
+		}
+		posts = append(posts, htmlPost{doc, template.HTML(doc.Content), attachments})
+	}
+
+	var b strings.Builder
+	err := htmlTemplate.Execute(&b, struct {
+		Title  string
+		Thread bool
+		Posts  []htmlPost
+	}{title, thread, posts})
+	if err != nil {
+		return "", fmt.Errorf("error executing HTML template: %v", err)
+	}
+	return b.String(), nil
+}
+
+// attachmentLabel returns the name (alt text) of the attachment, or its type if it has none
+func attachmentLabel(attachment SummaryAttachment) string {
+	if attachment.Name != "" {
+		return singleLine(attachment.Name)
+	}
+	if attachment.MediaType != "" {
+		return attachment.MediaType
+	}
+	return attachment.Type
+}
+
+// attachmentKind returns the kind of attachment (image, video, audio, etc.), based on its media type or type
+func attachmentKind(attachment SummaryAttachment) string {
+	if mediaType, _, found := strings.Cut(attachment.MediaType, "/"); found {
+		return mediaType
+	}
+	return strings.ToLower(attachment.Type)
+}
@@ -0,0 +1,164 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Hello world", "Hello world"},
+		{"a](javascript:alert(1))", `a\]\(javascript:alert\(1\)\)`},
+		{"*bold* _em_ `code`", "\\*bold\\* \\_em\\_ \\`code\\`"},
+		{"a | b", `a \| b`},
+		{`<img src=x onerror="alert(1)">`, `&lt;img src=x onerror=&#34;alert\(1\)&#34;&gt;`},
+		{`\`, `\\`},
+	}
+	for _, test := range tests {
+		if got := escapeMarkdown(test.text); got != test.want {
+			t.Errorf("escapeMarkdown(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestMarkdownLink(t *testing.T) {
+	tests := []struct {
+		text string
+		url  string
+		want string
+	}{
+		{"Alice", "https://example.com/@alice", `[Alice](https://example.com/@alice)`},
+		{"A [photo]", "https://example.com/a (1).png", `[A \[photo\]](https://example.com/a%20%281%29.png)`},
+		{"Evil", "javascript:alert(1)", `Evil`},
+	}
+	for _, test := range tests {
+		if got := markdownLink(test.text, test.url); got != test.want {
+			t.Errorf("markdownLink(%q, %q) = %q, want %q", test.text, test.url, got, test.want)
+		}
+	}
+}
+
+func TestFormatMarkdownEscapesRemoteData(t *testing.T) {
+	note := `{"type":"Note","id":"https://example.com/notes/1","name":"<script>alert(1)</script>","content":"<p>Hi</p>",
+		"attachment":[{"type":"Document","mediaType":"image/png","url":"https://example.com/a.png","name":"alt](https://evil.example)"}]}`
+	markdown, err := FormatMarkdown([]byte(note), Options{})
+	if err != nil {
+		t.Fatalf("FormatMarkdown() returned error: %v", err)
+	}
+	// The raw JSON is in a code block, only the rendered part matters
+	rendered, _, _ := strings.Cut(markdown, "<details>")
+	for _, unwanted := range []string{"<script>", "](https://evil.example)"} {
+		if strings.Contains(rendered, unwanted) {
+			t.Errorf("FormatMarkdown() contains %q:\n%s", unwanted, markdown)
+		}
+	}
+	if !strings.Contains(markdown, `![alt\]\(https://evil\.example\)](https://example.com/a.png)`) {
+		t.Errorf("FormatMarkdown() doesn't contain the escaped image:\n%s", markdown)
+	}
+}
+
+func TestFormatThread(t *testing.T) {
+	posts := [][]byte{
+		[]byte(`{"type":"Note","id":"https://example.com/notes/1","name":"First","content":"<p>One</p>"}`),
+		[]byte(`{"type":"Note","id":"https://example.com/notes/2","name":"Second","content":"<p>Two</p>","inReplyTo":"https://example.com/notes/1"}`),
+	}
+	markdown, err := FormatMarkdownThread(posts, Options{})
+	if err != nil {
+		t.Fatalf("FormatMarkdownThread() returned error: %v", err)
+	}
+	if !strings.HasPrefix(markdown, "# Thread: First\n") || strings.Index(markdown, "## First") > strings.Index(markdown, "## Second") {
+		t.Errorf("FormatMarkdownThread() doesn't contain the posts in order:\n%s", markdown)
+	}
+
+	html, err := FormatHTMLThread(posts, Options{})
+	if err != nil {
+		t.Fatalf("FormatHTMLThread() returned error: %v", err)
+	}
+	if strings.Count(html, "<article>") != 2 || !strings.Contains(html, "<h2>Second</h2>") {
+		t.Errorf("FormatHTMLThread() doesn't contain both posts:\n%s", html)
+	}
+
+	if _, err := FormatMarkdownThread(nil, Options{}); err == nil {
+		t.Errorf("FormatMarkdownThread(nil) didn't return an error")
+	}
+}
+
+func TestFormatMarkdownActivity(t *testing.T) {
+	note := `{"type":"Note","id":"https://a.example/notes/1","name":"Hello","content":"<p>The content</p>",
+		"attachment":[{"type":"Document","mediaType":"image/png","url":"https://a.example/a.png","name":"A cat"}]}`
+	opts := Options{Fetch: func(url string) ([]byte, error) {
+		if url == "https://a.example/notes/1" {
+			return []byte(note), nil
+		}
+		return nil, fmt.Errorf("not found: %s", url)
+	}}
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"embedded object", `{"type":"Create","id":"https://a.example/activities/1","object":` + note + `}`},
+		{"linked object", `{"type":"Announce","id":"https://a.example/activities/1","object":"https://a.example/notes/1"}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			markdown, err := FormatMarkdown([]byte(test.json), opts)
+			if err != nil {
+				t.Fatalf("FormatMarkdown() returned error: %v", err)
+			}
+			rendered, _, _ := strings.Cut(markdown, "<details>")
+			for _, want := range []string{"# Hello", "The content", "![A cat](https://a.example/a.png)", "| Activity ID | https://a\\.example/activities/1 |"} {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("FormatMarkdown() doesn't contain %q:\n%s", want, markdown)
+				}
+			}
+		})
+	}
+}
+
+func TestExportSensitiveMedia(t *testing.T) {
+	note := `{"type":"Note","id":"https://example.com/notes/1","sensitive":true,"content":"<p>hello</p>",` +
+		`"attachment":[{"type":"Document","mediaType":"image/png","url":"https://example.com/a.png","name":"A cat"},` +
+		`{"type":"Document","mediaType":"video/mp4","url":"https://example.com/b.mp4"}]}`
+	for _, test := range []struct {
+		name   string
+		format func([]byte, Options) (string, error)
+		hidden []string
+		shown  []string
+	}{
+		{"markdown", FormatMarkdown,
+			[]string{"- [A cat \\(sensitive\\)](https://example.com/a.png)", "- [video/mp4 \\(sensitive\\)](https://example.com/b.mp4)"},
+			[]string{"![A cat](https://example.com/a.png)"}},
+		{"html", FormatHTML,
+			[]string{`<a href="https://example.com/a.png">A cat (sensitive)</a>`, `<a href="https://example.com/b.mp4">video/mp4 (sensitive)</a>`},
+			[]string{`<img src="https://example.com/a.png" alt="A cat">`, `<video controls src="https://example.com/b.mp4"`}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			hidden, err := test.format([]byte(note), Options{})
+			if err != nil {
+				t.Fatalf("error exporting: %v", err)
+			}
+			for _, want := range test.hidden {
+				if !strings.Contains(hidden, want) {
+					t.Errorf("export doesn't contain %q:\n%s", want, hidden)
+				}
+			}
+			if strings.Contains(hidden, "<img") || strings.Contains(hidden, "<video") || strings.Contains(hidden, "![") {
+				t.Errorf("export embeds sensitive media:\n%s", hidden)
+			}
+
+			shown, err := test.format([]byte(note), Options{ShowSensitive: true})
+			if err != nil {
+				t.Fatalf("error exporting: %v", err)
+			}
+			for _, want := range test.shown {
+				if !strings.Contains(shown, want) {
+					t.Errorf("export with ShowSensitive doesn't contain %q:\n%s", want, shown)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"github.com/tidwall/gjson"
+	"golang.org/x/net/html"
+)
+
+// profileField is a PropertyValue entry of an actor (a Mastodon profile field)
+type profileField struct {
+	Name  string
+	Value string
+	// Link is the first link in the value, if any
+	Link string
+}
+
+// getProfileFields returns the PropertyValue entries found in the attachment array of an actor
+func getProfileFields(jsonStr []byte) []profileField {
+	var fields []profileField
+	for _, attachment := range resultArray(gjson.GetBytes(jsonStr, "attachment")) {
+		if attachment.Get("type").String() != "PropertyValue" {
+			continue
+		}
+		value := attachment.Get("value").String()
+		fields = append(fields, profileField{
+			Name:  singleLine(htmlToText(attachment.Get("name").String())),
+			Value: singleLine(htmlToText(value)),
+			Link:  firstLink(value),
+		})
+	}
+	return fields
+}
+
+// formatProfileFields formats the profile fields as a table. When link verification is enabled,
+// the linked pages are checked for a rel="me" link back to the actor (like Mastodon does).
+func formatProfileFields(jsonStr []byte, parts []string, opts Options, bold, green func(a ...interface{}) string) []string {
+	fields := getProfileFields(jsonStr)
+	if len(fields) == 0 {
+		return parts
+	}
+
+	// The actor might be linked using its profile page or its id
+	var actorURLs []string
+	for _, path := range []string{"url", "id"} {
+		actorURLs = append(actorURLs, resultStrings(gjson.GetBytes(jsonStr, path))...)
+	}
+
+	// Names are padded by their display width, so wide (CJK, emoji) and combining characters stay aligned
+	nameWidth := 0
+	for _, field := range fields {
+		nameWidth = max(nameWidth, uniseg.StringWidth(field.Name))
+	}
+
+	parts = append(parts, fmt.Sprintf("%s:", bold("Profile Fields")))
+	for _, field := range fields {
+		padding := strings.Repeat(" ", nameWidth-uniseg.StringWidth(field.Name))
+		row := fmt.Sprintf("  %s%s │ %s", field.Name, padding, field.Value)
+		if opts.VerifyLinks && field.Link != "" {
+			if verifyRelMe(field.Link, actorURLs, opts) {
+				row += " " + green("✔ verified")
+			} else {
+				row += " (not verified)"
+			}
+		}
+		parts = append(parts, row)
+	}
+	return parts
+}
+
+// verifyRelMe fetches the page and checks whether it contains a rel="me" link back to one of the actor URLs
+func verifyRelMe(pageURL string, actorURLs []string, opts Options) bool {
+	if opts.FetchPage == nil {
+		return false
+	}
+	page, err := opts.FetchPage(pageURL)
+	if err != nil {
+		return false
+	}
+
+	for _, link := range relMeLinks(page) {
+		for _, actorURL := range actorURLs {
+			if strings.TrimSuffix(link, "/") == strings.TrimSuffix(actorURL, "/") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// relMeLinks returns the href of all <a> and <link> elements with rel="me" in the HTML page
+func relMeLinks(page []byte) []string {
+	var links []string
+	tokenizer := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return links
+		}
+		if tokenType != html.StartTagToken && tokenType != html.SelfClosingTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		if token.Data != "a" && token.Data != "link" {
+			continue
+		}
+		var href string
+		var relMe bool
+		for _, attr := range token.Attr {
+			switch attr.Key {
+			case "href":
+				href = attr.Val
+			case "rel":
+				for _, rel := range strings.Fields(attr.Val) {
+					if strings.EqualFold(rel, "me") {
+						relMe = true
+					}
+				}
+			}
+		}
+		if relMe && href != "" {
+			links = append(links, href)
+		}
+	}
+}
+
+// firstLink returns the href of the first link in a HTML fragment
+func firstLink(fragment string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return ""
+		}
+		if tokenType != html.StartTagToken {
+			continue
+		}
+		token := tokenizer.Token()
+		if token.Data != "a" {
+			continue
+		}
+		for _, attr := range token.Attr {
+			if attr.Key == "href" {
+				return attr.Val
+			}
+		}
+	}
+}
+
+// singleLine collapses all whitespace (including newlines) into single spaces
+func singleLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// htmlToText returns the text of a HTML fragment, without any markup
+func htmlToText(fragment string) string {
+	var b strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			b.Write(tokenizer.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if name, _ := tokenizer.TagName(); string(name) == "br" {
+				b.WriteString("\n")
+			}
+		case html.EndTagToken:
+			// Keep paragraphs and other blocks apart
+			switch name, _ := tokenizer.TagName(); string(name) {
+			case "p", "div", "li", "h1", "h2", "h3", "h4", "h5", "h6", "blockquote":
+				b.WriteString("\n")
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rivo/uniseg"
+)
+
+func TestFormatProfileFieldsAlignment(t *testing.T) {
+	actor := `{"type":"Person","attachment":[
+		{"type":"PropertyValue","name":"Website","value":"a"},
+		{"type":"PropertyValue","name":"网站","value":"b"},
+		{"type":"PropertyValue","name":"🌐 Web","value":"c"},
+		{"type":"PropertyValue","name":"Café","value":"d"}]}`
+	parts := formatProfileFields([]byte(actor), nil, Options{}, fmt.Sprint, fmt.Sprint)
+	if len(parts) != 5 {
+		t.Fatalf("formatProfileFields() = %q, want a header and 4 rows", parts)
+	}
+	column := -1
+	for _, row := range parts[1:] {
+		before, _, found := strings.Cut(row, "│")
+		if !found {
+			t.Fatalf("row %q has no separator", row)
+		}
+		if width := uniseg.StringWidth(before); column == -1 {
+			column = width
+		} else if width != column {
+			t.Errorf("separator of row %q is at column %d, want %d", row, width, column)
+		}
+	}
+}
@@ -3,8 +3,9 @@ package formatter
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
-	"time"
 
 	h2m "github.com/JohannesKaufmann/html-to-markdown"
 	markdown "github.com/Klaus-Tockloth/go-term-markdown"
@@ -12,11 +13,32 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+// ansiEscapeRegex matches the ANSI escape sequences used for colors and text attributes
+var ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
 // Format takes ActivityPub data and returns a formatted string representation
 func Format(jsonData []byte) (string, error) {
+	return FormatWithOptions(jsonData, Options{})
+}
+
+// FormatWithOptions is like Format, but the summary can be tweaked using the options
+func FormatWithOptions(jsonData []byte, opts Options) (string, error) {
 	// Create a summary based on the object type
-	summary := createSummary(jsonData)
+	summary := createSummary(jsonData, opts)
 
+	// Content behind a content warning is hidden in the JSON as well
+	pretty, err := FormatJSON(HideSensitiveContent(jsonData, opts))
+	if err != nil {
+		return "", err
+	}
+
+	// Combine the full JSON first, followed by the summary at the bottom
+	result := fmt.Sprintf("%s\n\n%s", pretty, summary)
+	return result, nil
+}
+
+// FormatJSON beautifies the JSON data
+func FormatJSON(jsonData []byte) (string, error) {
 	// This might look unnecessary, but it is not in order to beautify the JSON.
 	// First Unmarkshall to get a map[string]interface{}
 	var data map[string]interface{}
@@ -29,29 +51,29 @@ func Format(jsonData []byte) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error beautifying JSON: %v", err)
 	}
+	return string(pretty), nil
+}
 
-	// Combine the full JSON first, followed by the summary at the bottom
-	result := fmt.Sprintf("%s\n\n%s", pretty, summary)
-	return result, nil
+// Summarize returns only the human-readable summary of the ActivityPub object or nodeinfo, without the JSON
+func Summarize(jsonData []byte, opts Options) string {
+	return createSummary(jsonData, opts)
 }
 
 // createSummary generates a human-readable summary of the ActivityPub object or nodeinfo
-func createSummary(jsonStr []byte) string {
+func createSummary(jsonStr []byte, opts Options) string {
+	colors := opts.theme().colors()
+
 	// Try to detect nodeinfo
 	if gjson.GetBytes(jsonStr, "software.name").Exists() && gjson.GetBytes(jsonStr, "version").Exists() {
-		return nodeInfoSummary(jsonStr)
+		return nodeInfoSummary(jsonStr, colors)
 	}
 
-	objectType := gjson.GetBytes(jsonStr, "type").String()
+	objectType := gjson.GetBytes(jsonStr, "type")
 
 	// Build a header with the object type
-	bold := color.New(color.Bold).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
+	bold, green, red := colors.Label, colors.URL, colors.Warning
 
-	header := fmt.Sprintf("%s: %s\n", bold("Type"), cyan(objectType))
+	header := fmt.Sprintf("%s: %s\n", bold("Type"), colors.Type(strings.Join(resultStrings(objectType), ", ")))
 
 	// Add sensitive content warning if present
 	if gjson.GetBytes(jsonStr, "sensitive").Bool() {
@@ -67,34 +89,17 @@ func createSummary(jsonStr []byte) string {
 		summaryParts = append(summaryParts, fmt.Sprintf("%s: %s", bold("Original URL"), green(id)))
 	}
 
-	// Process based on type
-	switch objectType {
-	case "Person", "Application", "Group", "Organization", "Service":
-		summaryParts = formatActor(jsonStr, summaryParts, bold, cyan, green, red, yellow)
-	case "Note", "Article", "Page", "Question":
-		summaryParts = formatContent(jsonStr, summaryParts, bold, green, yellow)
-	case "Create", "Update", "Delete", "Follow", "Add", "Remove", "Like", "Block", "Announce":
-		summaryParts = formatActivity(jsonStr, summaryParts, bold, green, yellow)
-	case "Collection", "OrderedCollection", "CollectionPage", "OrderedCollectionPage":
-		summaryParts = formatCollection(jsonStr, summaryParts, bold, green, yellow)
-	case "Image", "Audio", "Video", "Document":
-		summaryParts = formatMedia(jsonStr, summaryParts, bold, green, yellow)
-	case "Event":
-		summaryParts = formatEvent(jsonStr, summaryParts, bold, yellow)
-	case "Tombstone":
-		summaryParts = formatTombstone(jsonStr, summaryParts, bold, green, yellow)
+	// Process based on type, using the registered renderers
+	if renderer := rendererFor(objectType); renderer != nil {
+		summaryParts = renderer.Render(jsonStr, summaryParts, opts, colors)
 	}
 
 	return strings.Join(summaryParts, "\n")
 }
 
 // nodeInfoSummary generates a summary for nodeinfo objects
-func nodeInfoSummary(jsonStr []byte) string {
-	bold := color.New(color.Bold).SprintFunc()
-	cyan := color.New(color.FgCyan).SprintFunc()
-	green := color.New(color.FgGreen).SprintFunc()
-	yellow := color.New(color.FgYellow).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
+func nodeInfoSummary(jsonStr []byte, colors Colors) string {
+	bold, cyan, green, yellow, red := colors.Label, colors.Type, colors.URL, colors.Date, colors.Warning
 
 	parts := []string{}
 	parts = append(parts, fmt.Sprintf("%s: %s", bold("NodeInfo Version"), cyan(gjson.GetBytes(jsonStr, "version").String())))
@@ -145,13 +150,14 @@ func nodeInfoSummary(jsonStr []byte) string {
 }
 
 // formatActor formats actor-type objects (Person, Service, etc.)
-func formatActor(jsonStr []byte, parts []string, bold, cyan, green, red, yellow func(a ...interface{}) string) []string {
-	if name := gjson.GetBytes(jsonStr, "name").String(); name != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Name"), cyan(name)))
+func formatActor(jsonStr []byte, parts []string, opts Options, bold, cyan, green, red, yellow, username func(a ...interface{}) string) []string {
+	tags := gjson.GetBytes(jsonStr, "tag")
+	if name := getLocalized(jsonStr, "name", opts).Value; name != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Name"), cyan(replaceEmojiShortcodes(name, tags))))
 	}
 
 	if preferredUsername := gjson.GetBytes(jsonStr, "preferredUsername").String(); preferredUsername != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Username"), red(preferredUsername)))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Username"), username(preferredUsername)))
 	}
 
 	if url := gjson.GetBytes(jsonStr, "url").String(); url != "" {
@@ -161,15 +167,26 @@ func formatActor(jsonStr []byte, parts []string, bold, cyan, green, red, yellow
 	iconUrl := gjson.GetBytes(jsonStr, "icon.url").String()
 	if iconUrl != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Avatar"), green(iconUrl)))
+		if avatar := renderImage(iconUrl, gjson.GetBytes(jsonStr, "icon.blurhash").String(), avatarColumns, opts); avatar != "" {
+			parts = append(parts, avatar)
+		}
 	}
 
-	if summary := gjson.GetBytes(jsonStr, "summary").String(); summary != "" {
-		md := htmlToMarkdown(summary)
-		parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Summary"), renderMarkdown(md)))
+	summary := getLocalized(jsonStr, "summary", opts)
+	if summary.Value != "" {
+		md := replaceEmojiShortcodes(htmlToMarkdown(summary.Value), tags)
+		parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Summary"), renderMarkdown(md, opts)))
 	}
+	parts = formatLanguages(summary, parts, "Summary Languages", bold, yellow)
+
+	parts = formatProfileFields(jsonStr, parts, opts, bold, green)
+
+	parts = formatAccountStatus(jsonStr, parts, bold, green, red)
+
+	parts = formatMigration(jsonStr, parts, opts, bold, green, red)
 
 	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published, opts))))
 	}
 
 	if followers := gjson.GetBytes(jsonStr, "followers").String(); followers != "" {
@@ -180,166 +197,155 @@ func formatActor(jsonStr []byte, parts []string, bold, cyan, green, red, yellow
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Following"), green(following)))
 	}
 
+	parts = formatTags(tags, parts, opts, bold, green)
+
 	return parts
 }
 
 // formatContent formats content-type objects (Note, Article, Page, etc.)
-func formatContent(jsonStr []byte, parts []string, bold, green, yellow func(a ...interface{}) string) []string {
+func formatContent(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	if visibility := classifyVisibility(jsonStr); visibility != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Visibility"), yellow(visibility)))
+	}
+
 	// Show the name/title if present (especially for Page/thread)
-	if name := gjson.GetBytes(jsonStr, "name").String(); name != "" {
+	if name := getLocalized(jsonStr, "name", opts).Value; name != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Title"), name))
 	}
 
-	if content := gjson.GetBytes(jsonStr, "content").String(); content != "" {
-		md := htmlToMarkdown(content)
-		// Truncate the content if its too big.
-		if len(md) > 1200 {
-			md = md[:1197] + "..."
-		}
-		parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Content"), renderMarkdown(md)))
-	}
+	tags := gjson.GetBytes(jsonStr, "tag")
+	parts = formatContentBody(jsonStr, parts, opts, bold, yellow, red)
+
+	parts = formatQuotes(gjson.ParseBytes(jsonStr), parts, opts, bold, green, red)
 
 	// Check for attachments (images, videos, etc.)
 	attachments := gjson.GetBytes(jsonStr, "attachment").Array()
-	if len(attachments) > 0 {
-		parts = append(parts, fmt.Sprintf("%s:", bold("Attachments")))
-		for i, attachment := range attachments {
-			attachmentType := attachment.Get("type").String()
-			mediaType := attachment.Get("mediaType").String()
-			url := attachment.Get("url").String()
-			href := attachment.Get("href").String()
-			name := attachment.Get("name").String()
-
-			// Truncate long names
-			if len(name) > 100 {
-				name = name[:97] + "..."
-			}
-
-			attachmentInfo := fmt.Sprintf("  %d. %s", i+1, green(attachmentType))
-			if mediaType != "" {
-				attachmentInfo += fmt.Sprintf(" (%s)", mediaType)
-			}
-			if name != "" {
-				attachmentInfo += fmt.Sprintf(": %s", name)
-			}
-			parts = append(parts, attachmentInfo)
-
-			// For type Page and attachment type Link, show href if present
-			objectType := gjson.GetBytes(jsonStr, "type").String()
-			if objectType == "Page" && attachmentType == "Link" && href != "" {
-				parts = append(parts, fmt.Sprintf("     URL: %s", green(href)))
-			} else if url != "" {
-				parts = append(parts, fmt.Sprintf("     URL: %s", green(url)))
-			}
-		}
-	}
+	sensitive := gjson.GetBytes(jsonStr, "sensitive").Bool()
+	parts = formatAttachments(attachments, gjson.GetBytes(jsonStr, "type").String(), sensitive, parts, opts, bold, green, red)
 
 	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published, opts))))
 	}
 
-	if updated := gjson.GetBytes(jsonStr, "updated").String(); updated != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Updated"), yellow(formatDate(updated))))
+	if updated := formatUpdated(gjson.GetBytes(jsonStr, "published").String(), gjson.GetBytes(jsonStr, "updated").String(), opts); updated != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Updated"), yellow(updated)))
 	}
 
 	if attributedTo := gjson.GetBytes(jsonStr, "attributedTo").String(); attributedTo != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Author"), green(attributedTo)))
 	}
 
-	if to := gjson.GetBytes(jsonStr, "to").Array(); len(to) > 0 {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("To"), green(formatArray(to))))
-	}
+	parts = formatRecipients(jsonStr, parts, opts, bold, green)
 
-	if cc := gjson.GetBytes(jsonStr, "cc").Array(); len(cc) > 0 {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("CC"), green(formatArray(cc))))
-	}
+	parts = formatTags(tags, parts, opts, bold, green)
 
 	if inReplyTo := gjson.GetBytes(jsonStr, "inReplyTo").String(); inReplyTo != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("In Reply To"), green(inReplyTo)))
 	}
 
-	// Include endTime for Question type
-	if endTime := gjson.GetBytes(jsonStr, "endTime").String(); endTime != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("End Time"), yellow(formatDate(endTime))))
+	parts = formatPoll(jsonStr, parts, opts, bold, green, yellow, red)
+
+	return parts
+}
+
+// formatActivity formats activity-type objects (Create, Like, etc.)
+func formatActivity(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	if visibility := classifyVisibility(jsonStr); visibility != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Visibility"), yellow(visibility)))
 	}
 
-	// Include options (oneOf/anyOf) for Question type
-	options := gjson.GetBytes(jsonStr, "oneOf").Array()
-	if len(options) == 0 {
-		options = gjson.GetBytes(jsonStr, "anyOf").Array()
+	if actor := firstString(gjson.GetBytes(jsonStr, "actor")); actor != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Actor"), actor))
 	}
-	if len(options) > 0 {
-		parts = append(parts, fmt.Sprintf("%s:", bold("Poll Options")))
-		for i, opt := range options {
-			name := opt.Get("name").String()
-			parts = append(parts, fmt.Sprintf("  %d. %s", i+1, name))
+
+	activityType := gjson.GetBytes(jsonStr, "type").String()
+	tags := gjson.GetBytes(jsonStr, "tag")
+	if content := gjson.GetBytes(jsonStr, "content").String(); content != "" {
+		switch activityType {
+		case "Flag":
+			parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Report Comment"), renderMarkdown(htmlToMarkdown(content), opts)))
+		case "EmojiReact", "Like", "Dislike":
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Reaction"), replaceEmojiShortcodes(content, tags)))
 		}
+	} else if reaction := gjson.GetBytes(jsonStr, "_misskey_reaction").String(); reaction != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Reaction"), replaceEmojiShortcodes(reaction, tags)))
 	}
 
-	return parts
-}
+	// The object might be a single link or object, or an array (eg. the reported actor and posts of a Flag)
+	for _, object := range resultArray(gjson.GetBytes(jsonStr, "object")) {
+		parts = formatActivityObject(object, activityType, parts, opts, bold, green, yellow, red)
+	}
 
-// formatActivity formats activity-type objects (Create, Like, etc.)
-func formatActivity(jsonStr []byte, parts []string, bold, green, yellow func(a ...interface{}) string) []string {
-	if actor := gjson.GetBytes(jsonStr, "actor").String(); actor != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Actor"), actor))
+	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published, opts))))
 	}
 
-	if object := gjson.GetBytes(jsonStr, "object").String(); object != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Object"), object))
-	} else if gjson.GetBytes(jsonStr, "object").IsObject() {
-		objectType := gjson.GetBytes(jsonStr, "object.type").String()
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Object Type"), yellow(objectType)))
+	if target := firstString(gjson.GetBytes(jsonStr, "target")); target != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Target"), target))
+	}
 
-		if content := gjson.GetBytes(jsonStr, "object.content").String(); content != "" {
-			md := htmlToMarkdown(content)
-			parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Content"), renderMarkdown(md)))
-		}
+	if activityType == "Move" {
+		parts = formatMove(jsonStr, parts, opts, bold, green, red)
+	}
 
-		// Check for attachments in the object
-		attachments := gjson.GetBytes(jsonStr, "object.attachment").Array()
-		if len(attachments) > 0 {
-			parts = append(parts, fmt.Sprintf("%s:", bold("Attachments")))
-			for i, attachment := range attachments {
-				attachmentType := attachment.Get("type").String()
-				mediaType := attachment.Get("mediaType").String()
-				url := attachment.Get("url").String()
-				name := attachment.Get("name").String()
-
-				// Truncate long names
-				if len(name) > 100 {
-					name = name[:97] + "..."
-				}
+	parts = formatRecipients(jsonStr, parts, opts, bold, green)
 
-				attachmentInfo := fmt.Sprintf("  %d. %s", i+1, green(attachmentType))
-				if mediaType != "" {
-					attachmentInfo += fmt.Sprintf(" (%s)", mediaType)
-				}
-				if name != "" {
-					attachmentInfo += fmt.Sprintf(": %s", name)
-				}
-				parts = append(parts, attachmentInfo)
+	return parts
+}
 
-				if url != "" {
-					parts = append(parts, fmt.Sprintf("     URL: %s", url))
-				}
-			}
+// formatActivityObject formats the object of an activity. Embedded activities (like the Follow
+// inside an Accept, or the Like inside an Undo) are rendered recursively.
+func formatActivityObject(object gjson.Result, activityType string, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	if !object.IsObject() {
+		label := "Object"
+		if activityType == "Flag" {
+			label = "Reported"
 		}
+		return append(parts, fmt.Sprintf("%s: %s", bold(label), object.String()))
 	}
 
-	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published))))
+	// Activities always have an actor
+	if object.Get("actor").Exists() {
+		parts = append(parts, fmt.Sprintf("%s:", bold("Object")))
+		return append(parts, boxed(createSummary([]byte(object.Raw), opts.nested())))
 	}
 
-	if target := gjson.GetBytes(jsonStr, "target").String(); target != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Target"), target))
+	objectJSON := []byte(object.Raw)
+	objectType := object.Get("type").String()
+	parts = append(parts, fmt.Sprintf("%s: %s", bold("Object Type"), yellow(objectType)))
+	if id := object.Get("id").String(); id != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Object"), id))
+	}
+
+	parts = formatContentBody(objectJSON, parts, opts, bold, yellow, red)
+
+	parts = formatQuotes(object, parts, opts, bold, green, red)
+
+	parts = formatTags(object.Get("tag"), parts, opts, bold, green)
+
+	// Check for attachments in the object
+	attachments := object.Get("attachment").Array()
+	parts = formatAttachments(attachments, objectType, object.Get("sensitive").Bool(), parts, opts, bold, green, red)
+
+	return parts
+}
+
+// formatRecipients formats the to/cc addressing of an object or activity
+func formatRecipients(jsonStr []byte, parts []string, opts Options, bold, green func(a ...interface{}) string) []string {
+	cache := make(map[string]string)
+	if to := resultStrings(gjson.GetBytes(jsonStr, "to")); len(to) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("To"), green(formatList(describeRecipients(to, opts, cache)))))
+	}
+
+	if cc := resultStrings(gjson.GetBytes(jsonStr, "cc")); len(cc) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("CC"), green(formatList(describeRecipients(cc, opts, cache)))))
 	}
 
 	return parts
 }
 
 // formatCollection formats collection-type objects
-func formatCollection(jsonStr []byte, parts []string, bold, green, yellow func(a ...interface{}) string) []string {
+func formatCollection(jsonStr []byte, parts []string, opts Options, bold, green, yellow func(a ...interface{}) string) []string {
 	if totalItems := gjson.GetBytes(jsonStr, "totalItems").Int(); totalItems > 0 {
 		parts = append(parts, fmt.Sprintf("%s: %d", bold("Total Items"), totalItems))
 	}
@@ -359,9 +365,7 @@ func formatCollection(jsonStr []byte, parts []string, bold, green, yellow func(a
 		parts = append(parts, fmt.Sprintf("%s:", bold("First Items")))
 		for i := 0; i < itemCount; i++ {
 			item := items[i].String()
-			if len(item) > 100 {
-				item = item[:97] + "..."
-			}
+			item = truncate(item, 100)
 			parts = append(parts, fmt.Sprintf("  - %s", item))
 		}
 
@@ -371,80 +375,133 @@ func formatCollection(jsonStr []byte, parts []string, bold, green, yellow func(a
 	}
 
 	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published, opts))))
 	}
 
 	return parts
 }
 
 // formatMedia formats media-type objects (Image, Video, etc.)
-func formatMedia(jsonStr []byte, parts []string, bold, green, yellow func(a ...interface{}) string) []string {
+func formatMedia(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
 	if name := gjson.GetBytes(jsonStr, "name").String(); name != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Title"), name))
 	}
 
-	if url := gjson.GetBytes(jsonStr, "url").String(); url != "" {
+	// The URL is either a plain link, or a list of Link objects (eg. PeerTube)
+	if urlResult := gjson.GetBytes(jsonStr, "url"); urlResult.Type == gjson.String {
+		url := urlResult.String()
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("URL"), green(url)))
+		if isImage(gjson.ParseBytes(jsonStr)) && (!gjson.GetBytes(jsonStr, "sensitive").Bool() || opts.ShowSensitive) {
+			if image := renderImage(url, gjson.GetBytes(jsonStr, "blurhash").String(), attachmentColumns, opts); image != "" {
+				parts = append(parts, image)
+			}
+		}
+	} else if links := resultArray(urlResult); len(links) > 0 {
+		parts = formatLinks(links, parts, bold, green)
 	}
 
 	if duration := gjson.GetBytes(jsonStr, "duration").String(); duration != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Duration"), duration))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Duration"), formatDuration(duration)))
 	}
 
+	parts = formatVideoProperties(jsonStr, parts, bold, green, red)
+
 	if published := gjson.GetBytes(jsonStr, "published").String(); published != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Published"), yellow(formatDate(published, opts))))
 	}
 
-	if attributedTo := gjson.GetBytes(jsonStr, "attributedTo").String(); attributedTo != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Author"), attributedTo))
-	}
+	parts = formatAttribution(jsonStr, parts, bold, green)
 
 	return parts
 }
 
 // formatEvent formats event-type objects
-func formatEvent(jsonStr []byte, parts []string, bold, yellow func(a ...interface{}) string) []string {
-	if name := gjson.GetBytes(jsonStr, "name").String(); name != "" {
+func formatEvent(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	if name := getLocalized(jsonStr, "name", opts).Value; name != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Title"), name))
 	}
 
-	if content := gjson.GetBytes(jsonStr, "content").String(); content != "" {
-		md := htmlToMarkdown(content)
-		parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Description"), renderMarkdown(md)))
+	content := getLocalized(jsonStr, "content", opts)
+	if content.Value != "" {
+		md := htmlToMarkdown(content.Value)
+		parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Description"), renderMarkdown(md, opts)))
 	}
+	parts = formatLanguages(content, parts, "Description Languages", bold, yellow)
 
 	if startTime := gjson.GetBytes(jsonStr, "startTime").String(); startTime != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Start Time"), yellow(formatDate(startTime))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Start Time"), yellow(formatDate(startTime, opts))))
 	}
 
 	if endTime := gjson.GetBytes(jsonStr, "endTime").String(); endTime != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("End Time"), yellow(formatDate(endTime))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("End Time"), yellow(formatDate(endTime, opts))))
 	}
 
-	if location := gjson.GetBytes(jsonStr, "location").String(); location != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Location"), location))
+	if timezone := gjson.GetBytes(jsonStr, "timezone").String(); timezone != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Timezone"), timezone))
 	}
 
+	parts = formatPlace(gjson.GetBytes(jsonStr, "location"), parts, bold, green)
+
+	parts = formatParticipation(jsonStr, parts, bold, green, red)
+
 	return parts
 }
 
 // formatTombstone formats tombstone-type objects
-func formatTombstone(jsonStr []byte, parts []string, bold, green, yellow func(a ...interface{}) string) []string {
+func formatTombstone(jsonStr []byte, parts []string, opts Options, bold, green, yellow func(a ...interface{}) string) []string {
 	if formerType := gjson.GetBytes(jsonStr, "formerType").String(); formerType != "" {
 		parts = append(parts, fmt.Sprintf("%s: %s", bold("Former Type"), formerType))
 	}
 
 	if deleted := gjson.GetBytes(jsonStr, "deleted").String(); deleted != "" {
-		parts = append(parts, fmt.Sprintf("%s: %s", bold("Deleted"), yellow(formatDate(deleted))))
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Deleted"), yellow(formatDate(deleted, opts))))
 	}
 
 	return parts
 }
 
 // Helper to convert HTML to Markdown and render to terminal
-func renderMarkdown(md string) string {
-	// width=80, no color override, no emoji, no images
-	return string(markdown.Render(md, 78, 2))
+func renderMarkdown(md string, opts Options) string {
+	// left padding of 2, no color override, no emoji, no images
+	rendered := string(markdown.Render(md, opts.width()-2, 2))
+	// The markdown renderer always uses colors, its palette only matches the default theme.
+	// Other themes (like monochrome and high-contrast) only keep its text attributes.
+	if color.NoColor {
+		rendered = ansiEscapeRegex.ReplaceAllString(rendered, "")
+	} else if opts.theme() != DefaultTheme {
+		rendered = stripANSIColors(rendered)
+	}
+	return rendered
+}
+
+// stripANSIColors removes the foreground and background colors from the ANSI escape sequences in the text,
+// keeping text attributes such as bold, italic and underline
+func stripANSIColors(text string) string {
+	return ansiEscapeRegex.ReplaceAllStringFunc(text, func(sequence string) string {
+		params := strings.Split(strings.TrimSuffix(strings.TrimPrefix(sequence, "\x1b["), "m"), ";")
+		var kept []string
+		for i := 0; i < len(params); i++ {
+			code, err := strconv.Atoi(params[i])
+			switch {
+			case err != nil && params[i] != "":
+				continue
+			case code == 38 || code == 48:
+				// Extended colors: 38;5;n (256 colors) or 38;2;r;g;b (true color)
+				if i+1 < len(params) && params[i+1] == "5" {
+					i += 2
+				} else if i+1 < len(params) && params[i+1] == "2" {
+					i += 4
+				}
+			case code >= 30 && code <= 49, code >= 90 && code <= 107:
+			default:
+				kept = append(kept, params[i])
+			}
+		}
+		if len(kept) == 0 {
+			return ""
+		}
+		return "\x1b[" + strings.Join(kept, ";") + "m"
+	})
 }
 
 // Replace stripHTML with htmlToMarkdown
@@ -457,26 +514,12 @@ func htmlToMarkdown(html string) string {
 	return md
 }
 
-// formatDate formats an ISO 8601 date string to a more readable format
-func formatDate(isoDate string) string {
-	t, err := time.Parse(time.RFC3339, isoDate)
-	if err != nil {
-		return isoDate
-	}
-	return t.Format("Jan 02, 2006 15:04:05")
-}
-
-// formatArray formats an array of values into a readable string
-func formatArray(values []gjson.Result) string {
-	if len(values) == 0 {
+// formatList formats a list of strings into a readable string
+func formatList(items []string) string {
+	if len(items) == 0 {
 		return ""
 	}
 
-	var items []string
-	for _, v := range values {
-		items = append(items, v.String())
-	}
-
 	if len(items) == 1 {
 		return items[0]
 	}
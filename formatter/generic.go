@@ -0,0 +1,97 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// genericProperties are the top-level properties shown by the generic renderer (or in the header of every summary)
+var genericProperties = map[string]bool{
+	"@context": true, "id": true, "type": true, "sensitive": true,
+	"name": true, "nameMap": true, "summary": true, "summaryMap": true, "content": true, "contentMap": true,
+	"url": true, "attributedTo": true, "published": true, "updated": true,
+	"icon": true, "image": true, "tag": true, "attachment": true, "to": true, "cc": true,
+}
+
+// formatGeneric formats the common properties of objects of an unknown type. The properties it
+// doesn't know about are listed at the end, so nothing silently disappears from the summary.
+func formatGeneric(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+	if name := getLocalized(jsonStr, "name", opts); name.Value != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("Name"), name.Value))
+	}
+	// A summary which is a content warning is shown (and folds the content) by formatContentBody
+	if summary := getLocalized(jsonStr, "summary", opts); summary.Value != "" && contentWarning(jsonStr, opts) == "" {
+		parts = append(parts, fmt.Sprintf("%s:\n%s", c.Label("Summary"), renderMarkdown(htmlToMarkdown(summary.Value), opts)))
+	}
+	parts = formatContentBody(jsonStr, parts, opts, c.Label, c.Date, c.Warning)
+
+	if urls := resultStrings(gjson.GetBytes(jsonStr, "url")); len(urls) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("URL"), c.URL(formatList(urls))))
+	}
+	if attributedTo := resultStrings(gjson.GetBytes(jsonStr, "attributedTo")); len(attributedTo) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("Author"), formatList(attributedTo)))
+	}
+	published := gjson.GetBytes(jsonStr, "published").String()
+	if published != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("Published"), c.Date(formatDate(published, opts))))
+	}
+	if updated := formatUpdated(published, gjson.GetBytes(jsonStr, "updated").String(), opts); updated != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("Updated"), c.Date(updated)))
+	}
+
+	for _, property := range []struct{ path, label string }{
+		{"icon", "Icon"},
+		{"image", "Image"},
+	} {
+		value := gjson.GetBytes(jsonStr, property.path)
+		if url := imageURL(value); url != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", c.Label(property.label), c.URL(url)))
+			if image := renderImage(url, value.Get("blurhash").String(), avatarColumns, opts); image != "" {
+				parts = append(parts, image)
+			}
+		}
+	}
+
+	parts = formatRecipients(jsonStr, parts, opts, c.Label, c.URL)
+	parts = formatTags(gjson.GetBytes(jsonStr, "tag"), parts, opts, c.Label, c.URL)
+
+	attachments := gjson.GetBytes(jsonStr, "attachment").Array()
+	parts = formatAttachments(attachments, "", gjson.GetBytes(jsonStr, "sensitive").Bool(), parts, opts, c.Label, c.URL, c.Warning)
+
+	if other := otherProperties(jsonStr); len(other) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", c.Label("Other Properties"), c.Date(strings.Join(other, ", "))))
+	}
+	return parts
+}
+
+// imageURL returns the URL of an icon or image property, which is either a link or an Image object
+func imageURL(value gjson.Result) string {
+	for _, image := range resultArray(value) {
+		if !image.IsObject() {
+			return image.String()
+		}
+		if url := firstString(image.Get("url")); url != "" {
+			return url
+		}
+		if href := image.Get("href").String(); href != "" {
+			return href
+		}
+	}
+	return ""
+}
+
+// otherProperties returns the (sorted) top-level keys of the object that are not shown by the generic renderer
+func otherProperties(jsonStr []byte) []string {
+	var keys []string
+	gjson.ParseBytes(jsonStr).ForEach(func(key, _ gjson.Result) bool {
+		if !genericProperties[key.String()] {
+			keys = append(keys, key.String())
+		}
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,60 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestFormatGenericContent(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+	tests := []struct {
+		name     string
+		json     string
+		opts     Options
+		want     []string
+		unwanted []string
+	}{
+		{
+			name: "content",
+			json: `{"type":"ChatMessage","content":"<p>hello</p>"}`,
+			want: []string{"Content:", "hello"},
+		},
+		{
+			name:     "content warning",
+			json:     `{"type":"ChatMessage","summary":"spoilers","sensitive":true,"content":"<p>secret</p>"}`,
+			want:     []string{"Content Warning: spoilers", "hidden behind the content warning"},
+			unwanted: []string{"secret", "Summary:"},
+		},
+		{
+			name: "shown sensitive content",
+			json: `{"type":"ChatMessage","summary":"spoilers","sensitive":true,"content":"<p>secret</p>"}`,
+			opts: Options{ShowSensitive: true},
+			want: []string{"Content Warning: spoilers", "secret"},
+		},
+		{
+			name:     "truncated content",
+			json:     `{"type":"ChatMessage","content":"<p>` + strings.Repeat("a", 50) + `b</p>"}`,
+			opts:     Options{MaxContent: 20},
+			want:     []string{"aaaaaaaaaaaaaaaaa..."},
+			unwanted: []string{"b"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := strings.Join(formatGeneric([]byte(test.json), nil, test.opts, MonochromeTheme.colors()), "\n")
+			for _, want := range test.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("formatGeneric() =\n%s\nwant it to contain %q", output, want)
+				}
+			}
+			for _, unwanted := range test.unwanted {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("formatGeneric() =\n%s\ndoesn't want it to contain %q", output, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxCollectionItems is the maximum number of dereferenced collection items that are shown
+const maxCollectionItems = 10
+
+// subscriberCountPaths are the (non-standard) properties used by software to expose the number of subscribers
+var subscriberCountPaths = []string{
+	"subscriberCount",
+	"subscribersCount",
+	"followersCount",
+	"followers.totalItems",
+}
+
+// formatGroup formats the community specific parts of Group actors (Lemmy, Mbin, PeerTube channels, Guppe, etc.)
+func formatGroup(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	if restricted := gjson.GetBytes(jsonStr, "postingRestrictedToMods"); restricted.Exists() {
+		value := green("false")
+		if restricted.Bool() {
+			value = yellow("true")
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Posting Restricted To Moderators"), value))
+	}
+
+	for _, path := range subscriberCountPaths {
+		if count := gjson.GetBytes(jsonStr, path); count.Exists() {
+			parts = append(parts, fmt.Sprintf("%s: %d", bold("Subscribers"), count.Int()))
+			break
+		}
+	}
+
+	// Lemmy uses attributedTo for the moderators collection, Mbin uses moderators.
+	// PeerTube channels are attributed to the account that owns the channel.
+	moderators := firstString(gjson.GetBytes(jsonStr, "moderators"))
+	if moderators == "" && gjson.GetBytes(jsonStr, "attributedTo").Type == gjson.String {
+		moderators = gjson.GetBytes(jsonStr, "attributedTo").String()
+	}
+	if moderators != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Moderators"), green(moderators)))
+		if opts.ResolveCommunity {
+			parts = formatModerators(moderators, parts, opts, red)
+		}
+	} else if owners := resultStrings(gjson.GetBytes(jsonStr, "attributedTo")); len(owners) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Owned By"), green(formatList(owners))))
+	}
+
+	if featured := firstString(gjson.GetBytes(jsonStr, "featured")); featured != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Featured"), green(featured)))
+		if opts.ResolveCommunity {
+			total, items, ok := fetchCollection(featured, opts)
+			if !ok {
+				parts = append(parts, fmt.Sprintf("  %s", red("Featured posts could not be fetched")))
+			} else {
+				parts = append(parts, fmt.Sprintf("  %d featured posts", total))
+				for _, item := range items {
+					parts = append(parts, fmt.Sprintf("  - %s", describeItem(item)))
+				}
+			}
+		}
+	}
+
+	if outbox := firstString(gjson.GetBytes(jsonStr, "outbox")); outbox != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Outbox"), green(outbox)))
+		if opts.ResolveCommunity {
+			if total, _, ok := fetchCollection(outbox, opts); !ok {
+				parts = append(parts, fmt.Sprintf("  %s", red("Outbox could not be fetched")))
+			} else {
+				parts = append(parts, fmt.Sprintf("  %d activities", total))
+			}
+		}
+	}
+
+	return parts
+}
+
+// formatModerators dereferences the moderators collection, showing the handles of the moderators
+func formatModerators(moderatorsURL string, parts []string, opts Options, red func(a ...interface{}) string) []string {
+	total, items, ok := fetchCollection(moderatorsURL, opts)
+	if !ok {
+		return append(parts, fmt.Sprintf("  %s", red("Moderators could not be fetched")))
+	}
+	if len(items) == 0 {
+		// It might be a single actor instead of a collection
+		if actor := opts.fetch(moderatorsURL); actor != nil && gjson.GetBytes(actor, "preferredUsername").Exists() {
+			return append(parts, fmt.Sprintf("  - %s", actorHandle(actor, moderatorsURL)))
+		}
+		return parts
+	}
+	for _, item := range items {
+		actorURL := firstString(item)
+		if actor := opts.fetch(actorURL); actor != nil {
+			parts = append(parts, fmt.Sprintf("  - %s", actorHandle(actor, actorURL)))
+		} else {
+			parts = append(parts, fmt.Sprintf("  - %s", actorURL))
+		}
+	}
+	if total > int64(len(items)) {
+		parts = append(parts, fmt.Sprintf("  ... and %d more moderators", total-int64(len(items))))
+	}
+	return parts
+}
+
+// fetchCollection dereferences a collection, returning its total number of items and
+// (at most maxCollectionItems of) the items in the collection or in its first page.
+// False is returned when the collection couldn't be fetched.
+func fetchCollection(collectionURL string, opts Options) (int64, []gjson.Result, bool) {
+	collection := opts.fetch(collectionURL)
+	if collection == nil {
+		return 0, nil, false
+	}
+	total := gjson.GetBytes(collection, "totalItems").Int()
+
+	items := collectionItems(gjson.ParseBytes(collection))
+	if len(items) == 0 {
+		// The items might be in the first page, which is either embedded or a link
+		first := gjson.GetBytes(collection, "first")
+		if first.IsObject() {
+			items = collectionItems(first)
+		} else if page := opts.fetch(first.String()); page != nil {
+			items = collectionItems(gjson.ParseBytes(page))
+		}
+	}
+	if total == 0 {
+		total = int64(len(items))
+	}
+	if len(items) > maxCollectionItems {
+		items = items[:maxCollectionItems]
+	}
+	return total, items, true
+}
+
+// collectionItems returns the items of a collection or collection page
+func collectionItems(collection gjson.Result) []gjson.Result {
+	items := collection.Get("orderedItems").Array()
+	if len(items) == 0 {
+		items = collection.Get("items").Array()
+	}
+	return items
+}
+
+// describeItem returns a short description of a collection item, which is either a link or an embedded object
+func describeItem(item gjson.Result) string {
+	if !item.IsObject() {
+		return item.String()
+	}
+	description := item.Get("type").String()
+	if name := item.Get("name").String(); name != "" {
+		description += ": " + name
+	}
+	if id := item.Get("id").String(); id != "" {
+		description += fmt.Sprintf(" (%s)", id)
+	}
+	return description
+}
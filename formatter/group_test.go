@@ -0,0 +1,57 @@
+package formatter
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestFormatGroupCollections(t *testing.T) {
+	group := []byte(`{"type":"Group","featured":"https://a.example/c/go/featured","outbox":"https://a.example/c/go/outbox"}`)
+	tests := []struct {
+		name    string
+		objects map[string]string
+		want    []string
+	}{
+		{
+			name: "fetched",
+			objects: map[string]string{
+				"https://a.example/c/go/featured": `{"type":"OrderedCollection","totalItems":0,"orderedItems":[]}`,
+				"https://a.example/c/go/outbox":   `{"type":"OrderedCollection","totalItems":42}`,
+			},
+			want: []string{
+				"Featured: https://a.example/c/go/featured",
+				"  0 featured posts",
+				"Outbox: https://a.example/c/go/outbox",
+				"  42 activities",
+			},
+		},
+		{
+			name:    "fetch failed",
+			objects: map[string]string{},
+			want: []string{
+				"Featured: https://a.example/c/go/featured",
+				"  Featured posts could not be fetched",
+				"Outbox: https://a.example/c/go/outbox",
+				"  Outbox could not be fetched",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts := Options{
+				ResolveCommunity: true,
+				Fetch: func(url string) ([]byte, error) {
+					if object, ok := test.objects[url]; ok {
+						return []byte(object), nil
+					}
+					return nil, fmt.Errorf("not found: %s", url)
+				},
+			}
+			got := formatGroup(group, nil, opts, fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("formatGroup() =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/disintegration/imaging"
+	"github.com/eliukblau/pixterm/pkg/ansimage"
+	"github.com/mattn/go-sixel"
+	"github.com/tidwall/gjson"
+)
+
+// Supported protocols to show images in the terminal
+const (
+	ImageProtocolAuto   = "auto"
+	ImageProtocolANSI   = "ansi"
+	ImageProtocolKitty  = "kitty"
+	ImageProtocolITerm2 = "iterm2"
+	ImageProtocolSixel  = "sixel"
+)
+
+// Width of the rendered images in terminal columns
+const (
+	avatarColumns     = 16
+	attachmentColumns = 40
+)
+
+// maxImagePixels is the maximum number of pixels (width × height) of images that are decoded,
+// a small file can declare huge dimensions and make decoding allocate gigabytes
+const maxImagePixels = 50_000_000
+
+// isImage returns true if the object or attachment is an image
+func isImage(object gjson.Result) bool {
+	return object.Get("type").String() == "Image" || strings.HasPrefix(object.Get("mediaType").String(), "image/")
+}
+
+// detectImageProtocol guesses the best image protocol supported by the terminal, based on the environment
+func detectImageProtocol() string {
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || termProgram == "ghostty":
+		return ImageProtocolKitty
+	case termProgram == "iTerm.app" || termProgram == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return ImageProtocolITerm2
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm"):
+		return ImageProtocolSixel
+	}
+	return ImageProtocolANSI
+}
+
+// renderImage downloads and renders an image in the terminal, using the given width in columns.
+// When the image can't be fetched or decoded, the blurhash (if any) is used as a color preview instead.
+// An empty string is returned if there is nothing to show.
+func renderImage(imageURL, hash string, columns int, opts Options) string {
+	if !opts.Images {
+		return ""
+	}
+
+	if opts.FetchImage != nil && imageURL != "" {
+		if data, err := opts.FetchImage(imageURL); err == nil {
+			if img, err := decodeImage(data); err == nil {
+				if rendered, err := encodeImage(img, columns, opts.ImageProtocol); err == nil {
+					return rendered
+				}
+			}
+		}
+	}
+
+	if hash != "" {
+		if preview := renderBlurhash(hash, columns/2); preview != "" {
+			return preview
+		}
+	}
+	return ""
+}
+
+// decodeImage decodes the (untrusted) image data, after checking its dimensions aren't too large
+func decodeImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxImagePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are not supported", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// renderBlurhash renders the blurhash of an image as small color preview using ANSI blocks
+func renderBlurhash(hash string, columns int) string {
+	img, err := blurhash.Decode(hash, columns, columns/2, 1)
+	if err != nil {
+		return ""
+	}
+	rendered, err := encodeANSI(img, columns)
+	if err != nil {
+		return ""
+	}
+	return rendered
+}
+
+// encodeImage encodes the image for the terminal using the image protocol
+func encodeImage(img image.Image, columns int, protocol string) (string, error) {
+	if protocol == "" || protocol == ImageProtocolAuto {
+		protocol = detectImageProtocol()
+	}
+	switch protocol {
+	case ImageProtocolKitty:
+		return encodeKitty(img, columns)
+	case ImageProtocolITerm2:
+		return encodeITerm2(img, columns)
+	case ImageProtocolSixel:
+		return encodeSixel(img, columns)
+	case ImageProtocolANSI:
+		return encodeANSI(img, columns)
+	}
+	return "", fmt.Errorf("unknown image protocol: %s", protocol)
+}
+
+// encodeANSI renders the image using colored unicode half blocks, which works in any true color terminal
+func encodeANSI(img image.Image, columns int) (string, error) {
+	// Every terminal row contains two pixels, the image is fitted in a square area
+	ansi, err := ansimage.NewScaledFromImage(img, columns, columns, color.Black, ansimage.ScaleModeFit, ansimage.NoDithering)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(ansi.Render(), "\n"), nil
+}
+
+// encodeKitty renders the image using the kitty graphics protocol
+func encodeKitty(img image.Image, columns int) (string, error) {
+	encoded, err := encodePNG(img, columns)
+	if err != nil {
+		return "", err
+	}
+
+	// The payload is transmitted in chunks of at most 4096 bytes
+	var b strings.Builder
+	for i := 0; i < len(encoded); i += 4096 {
+		end := min(i+4096, len(encoded))
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,c=%d,m=%d;%s\x1b\\", columns, more, encoded[i:end])
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, encoded[i:end])
+		}
+	}
+	return b.String(), nil
+}
+
+// encodeITerm2 renders the image using the inline images protocol of iTerm2
+func encodeITerm2(img image.Image, columns int) (string, error) {
+	encoded, err := encodePNG(img, columns)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;preserveAspectRatio=1:%s\a", columns, encoded), nil
+}
+
+// encodeSixel renders the image using sixel graphics
+func encodeSixel(img image.Image, columns int) (string, error) {
+	// Assume a cell width of about 10 pixels
+	img = imaging.Fit(img, columns*10, columns*10, imaging.Lanczos)
+	var buf bytes.Buffer
+	if err := sixel.NewEncoder(&buf).Encode(img); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// encodePNG downscales the image and returns it as a base64 encoded PNG
+func encodePNG(img image.Image, columns int) (string, error) {
+	// Avoid sending huge images to the terminal, assume a cell width of about 10 pixels
+	img = imaging.Fit(img, columns*10, columns*10, imaging.Lanczos)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
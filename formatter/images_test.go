@@ -0,0 +1,44 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngWithSize returns a valid 1x1 PNG, of which the header declares the given dimensions
+func pngWithSize(t *testing.T, width, height uint32) []byte {
+	var b bytes.Buffer
+	if err := png.Encode(&b, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("error encoding PNG: %v", err)
+	}
+	data := b.Bytes()
+	// The IHDR chunk directly follows the 8 byte signature: length (4), type (4), width (4), height (4), ...
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestDecodeImage(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"small image", pngWithSize(t, 1, 1), false},
+		{"huge dimensions", pngWithSize(t, 100000, 100000), true},
+		{"not an image", []byte("<html></html>"), true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeImage(test.data)
+			if (err != nil) != test.wantErr {
+				t.Errorf("decodeImage() error = %v, want error %v", err, test.wantErr)
+			}
+		})
+	}
+}
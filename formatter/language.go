@@ -0,0 +1,86 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// localized is a natural language value which might be available in multiple languages
+type localized struct {
+	Value string
+	// Language of the value, empty when unknown
+	Language string
+	// Languages available in the *Map variant of the property
+	Available []string
+}
+
+// getLocalized returns the value of a natural language property (like content), taking the
+// *Map variant (like contentMap) into account. The first available preferred language
+// is picked, otherwise the plain value is used, falling back to the first language in the map.
+func getLocalized(jsonStr []byte, path string, opts Options) localized {
+	plain := gjson.GetBytes(jsonStr, path).String()
+	valueMap := gjson.GetBytes(jsonStr, path+"Map")
+	if !valueMap.IsObject() {
+		return localized{Value: plain}
+	}
+
+	var result localized
+	values := make(map[string]string)
+	valueMap.ForEach(func(key, value gjson.Result) bool {
+		result.Available = append(result.Available, key.String())
+		values[key.String()] = value.String()
+		return true
+	})
+
+	for _, preferred := range opts.Languages {
+		for _, language := range result.Available {
+			if languageMatches(preferred, language) && values[language] != "" {
+				result.Value = values[language]
+				result.Language = language
+				return result
+			}
+		}
+	}
+
+	if plain != "" {
+		result.Value = plain
+		return result
+	}
+	for _, language := range result.Available {
+		if values[language] != "" {
+			result.Value = values[language]
+			result.Language = language
+			break
+		}
+	}
+	return result
+}
+
+// languageMatches returns true if the BCP47 language tag matches the preferred language,
+// ignoring the region when only one of them has one (eg. "en" matches "en-US")
+func languageMatches(preferred, language string) bool {
+	preferred = strings.ToLower(strings.ReplaceAll(preferred, "_", "-"))
+	language = strings.ToLower(strings.ReplaceAll(language, "_", "-"))
+	if preferred == language {
+		return true
+	}
+	return strings.SplitN(preferred, "-", 2)[0] == strings.SplitN(language, "-", 2)[0] &&
+		(!strings.Contains(preferred, "-") || !strings.Contains(language, "-"))
+}
+
+// formatLanguages formats the available languages, highlighting the one that is shown
+func formatLanguages(value localized, parts []string, label string, bold, yellow func(a ...interface{}) string) []string {
+	if len(value.Available) == 0 {
+		return parts
+	}
+	var languages []string
+	for _, language := range value.Available {
+		if language == value.Language {
+			language = yellow(language + " (shown)")
+		}
+		languages = append(languages, language)
+	}
+	return append(parts, fmt.Sprintf("%s: %s", bold(label), strings.Join(languages, ", ")))
+}
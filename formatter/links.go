@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// Link is a link found in an ActivityPub object, which can be followed to resolve the linked object
+type Link struct {
+	// Label describes the relation to the object, e.g. "Author" or "In Reply To"
+	Label string
+	URL   string
+}
+
+// linkProperties are the properties linking to other objects, in the order they are listed
+var linkProperties = []struct{ path, label string }{
+	{"attributedTo", "Author"},
+	{"actor", "Actor"},
+	{"object", "Object"},
+	{"target", "Target"},
+	{"inReplyTo", "In Reply To"},
+	{"context", "Context"},
+	{"movedTo", "Moved To"},
+	{"alsoKnownAs", "Also Known As"},
+	{"replies", "Replies"},
+	{"outbox", "Outbox"},
+	{"followers", "Followers"},
+	{"following", "Following"},
+	{"featured", "Featured"},
+	{"moderators", "Moderators"},
+	{"first", "First Page"},
+	{"prev", "Previous Page"},
+	{"next", "Next Page"},
+	{"partOf", "Part Of"},
+}
+
+// Links returns the links to other objects found in the ActivityPub object: its author, the post it
+// replies to, quoted posts, mentions, attachments, collection items, etc. Every URL is only listed once.
+// Links to media files are left out, as they can't be resolved.
+func Links(jsonData []byte) []Link {
+	var links []Link
+	seen := map[string]bool{gjson.GetBytes(jsonData, "id").String(): true}
+	add := func(label, url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			links = append(links, Link{Label: label, URL: url})
+		}
+	}
+
+	for _, property := range linkProperties {
+		for _, url := range resultStrings(gjson.GetBytes(jsonData, property.path)) {
+			add(property.label, url)
+		}
+	}
+	for _, url := range getQuoteURLs(gjson.ParseBytes(jsonData)) {
+		add("Quote Of", url)
+	}
+	for _, tag := range resultArray(gjson.GetBytes(jsonData, "tag")) {
+		if tag.Get("type").String() == "Mention" {
+			add("Mention", tag.Get("href").String())
+		}
+	}
+	// Media attachments (images, videos, etc.) can't be resolved, only attachments linking to objects are listed
+	for _, attachment := range resultArray(gjson.GetBytes(jsonData, "attachment")) {
+		add("Attachment", attachmentObjectURL(attachment))
+	}
+	for _, item := range collectionItems(gjson.ParseBytes(jsonData)) {
+		add("Item", firstString(item))
+	}
+	return links
+}
+
+// attachmentObjectTypes are the types of attachments which are ActivityPub objects themselves, instead of media
+var attachmentObjectTypes = map[string]bool{"Note": true, "Article": true, "Page": true, "Question": true, "Event": true}
+
+// attachmentObjectURL returns the URL of the ActivityPub object the attachment links to,
+// or an empty string for media attachments (images, videos, etc.)
+func attachmentObjectURL(attachment gjson.Result) string {
+	if attachmentObjectTypes[attachment.Get("type").String()] {
+		return attachment.Get("id").String()
+	}
+	if attachment.Get("type").String() == "Link" && isActivityStreamsMediaType(attachment.Get("mediaType").String()) {
+		return attachment.Get("href").String()
+	}
+	for _, link := range resultArray(attachment.Get("url")) {
+		if link.IsObject() && isActivityStreamsMediaType(link.Get("mediaType").String()) {
+			return link.Get("href").String()
+		}
+	}
+	return ""
+}
+
+// linkURL returns the URL of a url property, which is a string, a Link object (using its href)
+// or an array of them (using the first one). Objects other than links use their id.
+func linkURL(value gjson.Result) string {
+	for _, link := range resultArray(value) {
+		if !link.IsObject() {
+			return link.String()
+		}
+		if href := link.Get("href").String(); href != "" {
+			return href
+		}
+		return link.Get("id").String()
+	}
+	return ""
+}
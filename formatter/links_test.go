@@ -0,0 +1,85 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+func TestLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []Link
+	}{
+		{
+			name: "note",
+			json: `{"id":"https://a.example/notes/2","attributedTo":"https://a.example/users/alice","inReplyTo":"https://b.example/notes/1",
+				"replies":{"id":"https://a.example/notes/2/replies"},"tag":[{"type":"Mention","href":"https://b.example/users/bob"},{"type":"Hashtag","href":"https://a.example/tags/go"}]}`,
+			want: []Link{
+				{"Author", "https://a.example/users/alice"},
+				{"In Reply To", "https://b.example/notes/1"},
+				{"Replies", "https://a.example/notes/2/replies"},
+				{"Mention", "https://b.example/users/bob"},
+			},
+		},
+		{
+			name: "duplicates and the object itself are left out",
+			json: `{"id":"https://a.example/notes/2","attributedTo":"https://a.example/users/alice","actor":"https://a.example/users/alice","context":"https://a.example/notes/2"}`,
+			want: []Link{{"Author", "https://a.example/users/alice"}},
+		},
+		{
+			name: "media attachments are left out",
+			json: `{"attachment":[{"type":"Document","mediaType":"image/png","url":"https://a.example/a.png"},
+				{"type":"Video","url":[{"type":"Link","mediaType":"video/mp4","href":"https://a.example/v.mp4"}]},
+				{"type":"PropertyValue","name":"Website","value":"https://a.example"}]}`,
+			want: nil,
+		},
+		{
+			name: "attachments linking to objects",
+			json: `{"attachment":[{"type":"Link","mediaType":"application/activity+json","href":"https://b.example/notes/1"},
+				{"type":"Video","url":[{"type":"Link","mediaType":"text/html","href":"https://c.example/w/1"},{"type":"Link","mediaType":"application/activity+json","href":"https://c.example/videos/1"}]},
+				{"type":"Note","id":"https://d.example/notes/1"}]}`,
+			want: []Link{
+				{"Attachment", "https://b.example/notes/1"},
+				{"Attachment", "https://c.example/videos/1"},
+				{"Attachment", "https://d.example/notes/1"},
+			},
+		},
+		{
+			name: "collection items",
+			json: `{"type":"OrderedCollectionPage","partOf":"https://a.example/outbox","orderedItems":["https://a.example/notes/1",{"id":"https://a.example/notes/2"}]}`,
+			want: []Link{
+				{"Part Of", "https://a.example/outbox"},
+				{"Item", "https://a.example/notes/1"},
+				{"Item", "https://a.example/notes/2"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Links([]byte(test.json)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Links() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{"url":"https://a.example/a.png"}`, "https://a.example/a.png"},
+		{`{"url":{"type":"Link","href":"https://a.example/a.png"}}`, "https://a.example/a.png"},
+		{`{"url":[{"type":"Link","mediaType":"video/mp4","href":"https://a.example/v.mp4"}]}`, "https://a.example/v.mp4"},
+		{`{"url":{"type":"Image","id":"https://a.example/image"}}`, "https://a.example/image"},
+		{`{}`, ""},
+	}
+	for _, test := range tests {
+		if got := linkURL(gjson.Get(test.json, "url")); got != test.want {
+			t.Errorf("linkURL(%s) = %q, want %q", test.json, got, test.want)
+		}
+	}
+}
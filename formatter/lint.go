@@ -0,0 +1,115 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// activityStreamsContext is the JSON-LD context every ActivityPub object should use
+const activityStreamsContext = "https://www.w3.org/ns/activitystreams"
+
+// Object types checked by Lint
+var (
+	lintActorTypes    = map[string]bool{"Person": true, "Application": true, "Organization": true, "Service": true, "Group": true}
+	lintContentTypes  = map[string]bool{"Note": true, "Article": true, "Page": true, "Question": true, "Event": true, "Image": true, "Audio": true, "Video": true, "Document": true}
+	lintActivityTypes = map[string]bool{
+		"Create": true, "Update": true, "Delete": true, "Follow": true, "Add": true, "Remove": true, "Like": true,
+		"Block": true, "Announce": true, "Move": true, "Undo": true, "Accept": true, "Reject": true, "Flag": true,
+		"EmojiReact": true, "Dislike": true, "Join": true, "Leave": true, "Invite": true,
+	}
+)
+
+// Lint checks the ActivityPub object for common problems (missing properties, invalid dates,
+// mismatching hosts, missing alt text, etc.), returning a description of every problem found
+func Lint(jsonData []byte) []string {
+	var problems []string
+	report := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+	if !gjson.ValidBytes(jsonData) {
+		return []string{"invalid JSON"}
+	}
+
+	context := gjson.GetBytes(jsonData, "@context")
+	if !context.Exists() {
+		report("@context is missing")
+	} else if !strings.Contains(context.Raw, activityStreamsContext) {
+		report("@context doesn't include %s", activityStreamsContext)
+	}
+
+	id := gjson.GetBytes(jsonData, "id").String()
+	if id == "" {
+		report("id is missing")
+	} else if !strings.HasPrefix(id, "https://") {
+		report("id is not an https URL: %s", id)
+	}
+
+	objectType := gjson.GetBytes(jsonData, "type").String()
+	if objectType == "" {
+		report("type is missing")
+	}
+
+	for _, path := range []string{"published", "updated", "startTime", "endTime"} {
+		if date := gjson.GetBytes(jsonData, path).String(); date != "" {
+			if _, err := time.Parse(time.RFC3339, date); err != nil {
+				report("%s is not a valid xsd:dateTime (RFC 3339): %s", path, date)
+			}
+		}
+	}
+	published, publishedErr := time.Parse(time.RFC3339, gjson.GetBytes(jsonData, "published").String())
+	updated, updatedErr := time.Parse(time.RFC3339, gjson.GetBytes(jsonData, "updated").String())
+	if publishedErr == nil && updatedErr == nil && updated.Before(published) {
+		report("updated (%s) is before published (%s)", updated.Format(time.RFC3339), published.Format(time.RFC3339))
+	}
+
+	switch {
+	case lintActorTypes[objectType]:
+		for _, path := range []string{"inbox", "outbox", "preferredUsername", "publicKey"} {
+			if !gjson.GetBytes(jsonData, path).Exists() {
+				report("%s is missing, which is required for actors by most software", path)
+			}
+		}
+		if owner := gjson.GetBytes(jsonData, "publicKey.owner").String(); owner != "" && id != "" && owner != id {
+			report("publicKey.owner (%s) doesn't match the id of the actor", owner)
+		}
+	case lintContentTypes[objectType]:
+		attributedTo := firstString(gjson.GetBytes(jsonData, "attributedTo"))
+		if attributedTo == "" {
+			report("attributedTo is missing")
+		} else if id != "" && urlHost(attributedTo) != urlHost(id) {
+			report("the id (%s) and attributedTo (%s) are on different hosts", urlHost(id), urlHost(attributedTo))
+		}
+		if !gjson.GetBytes(jsonData, "published").Exists() {
+			report("published is missing")
+		}
+		if len(resultStrings(gjson.GetBytes(jsonData, "to")))+len(resultStrings(gjson.GetBytes(jsonData, "cc"))) == 0 {
+			report("to and cc are empty, the object isn't addressed to anyone")
+		}
+	case lintActivityTypes[objectType]:
+		actor := firstString(gjson.GetBytes(jsonData, "actor"))
+		if actor == "" {
+			report("actor is missing")
+		} else if id != "" && urlHost(actor) != urlHost(id) {
+			report("the id (%s) and actor (%s) are on different hosts", urlHost(id), urlHost(actor))
+		}
+		if !gjson.GetBytes(jsonData, "object").Exists() {
+			report("object is missing")
+		}
+	}
+
+	for i, attachment := range resultArray(gjson.GetBytes(jsonData, "attachment")) {
+		if attachment.Get("type").String() == "PropertyValue" {
+			continue
+		}
+		if !attachment.Get("mediaType").Exists() && attachment.Get("type").String() != "Link" {
+			report("attachment %d has no mediaType", i+1)
+		}
+		if isVisualMedia(attachment) && strings.TrimSpace(attachment.Get("name").String()) == "" {
+			report("attachment %d is missing alt text (name)", i+1)
+		}
+	}
+	return problems
+}
@@ -0,0 +1,79 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	const context = `"@context":"https://www.w3.org/ns/activitystreams",`
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "valid note",
+			json: `{` + context + `"id":"https://a.example/notes/1","type":"Note","attributedTo":"https://a.example/users/alice",
+				"published":"2025-01-01T12:00:00Z","to":["https://www.w3.org/ns/activitystreams#Public"],
+				"attachment":[{"type":"Document","mediaType":"image/png","url":"https://a.example/a.png","name":"A cat"}]}`,
+			want: nil,
+		},
+		{
+			name: "invalid JSON",
+			json: `{"id":`,
+			want: []string{"invalid JSON"},
+		},
+		{
+			name: "missing basics",
+			json: `{}`,
+			want: []string{"@context is missing", "id is missing", "type is missing"},
+		},
+		{
+			name: "note problems",
+			json: `{"@context":["https://w3id.org/security/v1"],"id":"http://a.example/notes/1","type":"Note","attributedTo":"https://b.example/users/bob",
+				"published":"2025-01-01 12:00","updated":"2024-01-01T12:00:00Z",
+				"attachment":[{"type":"Document","mediaType":"image/png","url":"https://a.example/a.png"},{"type":"Document","url":"https://a.example/b.pdf","name":"PDF"}]}`,
+			want: []string{
+				"@context doesn't include https://www.w3.org/ns/activitystreams",
+				"id is not an https URL: http://a.example/notes/1",
+				"published is not a valid xsd:dateTime (RFC 3339): 2025-01-01 12:00",
+				"the id (a.example) and attributedTo (b.example) are on different hosts",
+				"to and cc are empty, the object isn't addressed to anyone",
+				"attachment 1 is missing alt text (name)",
+				"attachment 2 has no mediaType",
+			},
+		},
+		{
+			name: "updated before published",
+			json: `{` + context + `"id":"https://a.example/notes/1","type":"Note","attributedTo":"https://a.example/users/alice",
+				"published":"2025-01-02T00:00:00Z","updated":"2025-01-01T00:00:00Z","cc":["https://a.example/users/alice/followers"]}`,
+			want: []string{"updated (2025-01-01T00:00:00Z) is before published (2025-01-02T00:00:00Z)"},
+		},
+		{
+			name: "actor",
+			json: `{` + context + `"id":"https://a.example/users/alice","type":"Person","inbox":"https://a.example/users/alice/inbox",
+				"publicKey":{"owner":"https://a.example/users/mallory"}}`,
+			want: []string{
+				"outbox is missing, which is required for actors by most software",
+				"preferredUsername is missing, which is required for actors by most software",
+				"publicKey.owner (https://a.example/users/mallory) doesn't match the id of the actor",
+			},
+		},
+		{
+			name: "activity",
+			json: `{` + context + `"id":"https://a.example/activities/1","type":"Like","actor":"https://b.example/users/bob"}`,
+			want: []string{
+				"the id (a.example) and actor (b.example) are on different hosts",
+				"object is missing",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Lint([]byte(test.json)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Lint() =\n%q\nwant\n%q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package formatter
+
+import (
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxMoves is the maximum length of a movedTo chain that is followed
+const maxMoves = 10
+
+// formatAccountStatus formats the account flags of an actor (suspended, discoverable, etc.)
+func formatAccountStatus(jsonStr []byte, parts []string, bold, green, red func(a ...interface{}) string) []string {
+	flags := []struct {
+		path  string
+		label string
+		// warning is true if the flag being set is something to be aware of
+		warning bool
+	}{
+		{"suspended", "Suspended", true},
+		{"memorial", "Memorial", true},
+		{"manuallyApprovesFollowers", "Manually Approves Followers", false},
+		{"discoverable", "Discoverable", false},
+		{"indexable", "Indexable", false},
+	}
+	for _, flag := range flags {
+		value := gjson.GetBytes(jsonStr, flag.path)
+		if !value.Exists() || value.Type == gjson.Null {
+			continue
+		}
+		valueStr := fmt.Sprintf("%t", value.Bool())
+		if flag.warning && value.Bool() {
+			valueStr = red(valueStr)
+		} else if !flag.warning && value.Bool() {
+			valueStr = green(valueStr)
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", bold(flag.label), valueStr))
+	}
+	return parts
+}
+
+// formatMigration formats the movedTo and alsoKnownAs properties of an actor. When following moves is
+// enabled, the movedTo chain is walked and the resulting migration history is shown.
+func formatMigration(jsonStr []byte, parts []string, opts Options, bold, green, red func(a ...interface{}) string) []string {
+	movedTo := firstString(gjson.GetBytes(jsonStr, "movedTo"))
+	if movedTo != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Moved To"), red(movedTo)))
+	}
+
+	if aliases := resultStrings(gjson.GetBytes(jsonStr, "alsoKnownAs")); len(aliases) > 0 {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Also Known As"), green(formatList(aliases))))
+	}
+
+	if movedTo != "" && opts.FollowMoves && opts.Fetch != nil {
+		parts = formatMigrationHistory(jsonStr, parts, opts, bold, green, red)
+	}
+	return parts
+}
+
+// formatMigrationHistory walks the movedTo chain of an actor, verifying that every target
+// lists the previous account in its alsoKnownAs (otherwise the move isn't legitimate)
+func formatMigrationHistory(jsonStr []byte, parts []string, opts Options, bold, green, red func(a ...interface{}) string) []string {
+	sourceURL := gjson.GetBytes(jsonStr, "id").String()
+	source := jsonStr
+	seen := map[string]bool{sourceURL: true}
+
+	parts = append(parts, fmt.Sprintf("%s:", bold("Migration History")))
+	parts = append(parts, fmt.Sprintf("  1. %s", actorHandle(source, sourceURL)))
+	for i := 2; i <= maxMoves+1; i++ {
+		targetURL := firstString(gjson.GetBytes(source, "movedTo"))
+		if targetURL == "" {
+			break
+		}
+		if seen[targetURL] {
+			parts = append(parts, fmt.Sprintf("  %d. %s %s", i, targetURL, red("(loop detected, stopped)")))
+			break
+		}
+		seen[targetURL] = true
+
+		target := opts.fetch(targetURL)
+		if target == nil {
+			parts = append(parts, fmt.Sprintf("  %d. %s %s", i, targetURL, red("(could not be fetched)")))
+			break
+		}
+
+		verified := false
+		for _, alias := range resultStrings(gjson.GetBytes(target, "alsoKnownAs")) {
+			if alias == sourceURL {
+				verified = true
+				break
+			}
+		}
+		status := green("✔ lists previous account in alsoKnownAs")
+		if !verified {
+			status = red("✘ does not list previous account in alsoKnownAs")
+		}
+		parts = append(parts, fmt.Sprintf("  %d. → %s %s", i, actorHandle(target, targetURL), status))
+
+		source = target
+		sourceURL = gjson.GetBytes(target, "id").String()
+		if sourceURL == "" {
+			sourceURL = targetURL
+		}
+	}
+	return parts
+}
+
+// formatMove formats a Move activity, which is sent when an account migrates to another account
+func formatMove(jsonStr []byte, parts []string, opts Options, bold, green, red func(a ...interface{}) string) []string {
+	from := firstString(gjson.GetBytes(jsonStr, "object"))
+	to := firstString(gjson.GetBytes(jsonStr, "target"))
+	if from == "" || to == "" {
+		return parts
+	}
+	parts = append(parts, fmt.Sprintf("%s: %s → %s", bold("Migration"), green(from), green(to)))
+
+	if opts.FollowMoves {
+		if target := opts.fetch(to); target != nil {
+			for _, alias := range resultStrings(gjson.GetBytes(target, "alsoKnownAs")) {
+				if alias == from {
+					return append(parts, fmt.Sprintf("%s: %s", bold("Alias"), green("✔ target lists the account in alsoKnownAs")))
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Alias"), red("✘ target does not list the account in alsoKnownAs")))
+		}
+	}
+	return parts
+}
+
+// firstString returns the first string value (or id) of a result
+func firstString(result gjson.Result) string {
+	if values := resultStrings(result); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package formatter
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// DefaultMaxContent is the default maximum length of the content in the summary (in characters)
+	DefaultMaxContent = 1200
+	// defaultWidth is the width used when the terminal width can't be detected
+	defaultWidth = 80
+	// boxIndent is the number of columns used by boxed (nested) summaries
+	boxIndent = 4
+)
+
+// Options controls the optional parts of the summary
+type Options struct {
+	// Languages lists the preferred languages (BCP47) for content which is available in multiple languages
+	Languages []string
+
+	// ResolveRecipients dereferences the to/cc recipients, showing who they are instead of bare URLs
+	ResolveRecipients bool
+	// ResolveMentions dereferences mentioned actors, showing their display names
+	ResolveMentions bool
+	// VerifyLinks checks the links in actor profile fields for a rel="me" link back to the actor
+	VerifyLinks bool
+	// FollowMoves walks the movedTo chain of actors, verifying every move using alsoKnownAs
+	FollowMoves bool
+	// ResolveCommunity dereferences the moderators, featured posts and outbox of communities (Group actors)
+	ResolveCommunity bool
+	// ResolveQuotes fetches quoted posts and shows them inline, including their quote authorization
+	ResolveQuotes bool
+
+	// ShowSensitive shows content behind a content warning, and renders sensitive images
+	ShowSensitive bool
+
+	// MaxContent is the maximum length of the content in characters (grapheme clusters). Zero means
+	// DefaultMaxContent, a negative value means the content is never truncated.
+	MaxContent int
+	// Width is the width of the summary in columns, when zero the width of the terminal is used
+	Width int
+
+	// Timezone is used to show dates, when nil the local timezone is used
+	Timezone *time.Location
+
+	// Theme is used to color the summary, when nil the DefaultTheme is used
+	Theme *Theme
+
+	// Images renders avatars and image attachments in the terminal
+	Images bool
+	// ImageProtocol is the protocol used to render images (see ImageProtocolAuto and friends)
+	ImageProtocol string
+
+	// Fetch retrieves a remote ActivityPub object. It is used by the resolve options above,
+	// when nil nothing will be dereferenced.
+	Fetch func(url string) ([]byte, error)
+	// FetchImage downloads an image, it's required for rendering images. When it
+	// fails, the blurhash of the image is shown instead (if available).
+	FetchImage func(url string) ([]byte, error)
+	// FetchPage downloads a web page, it's required for verifying links
+	FetchPage func(url string) ([]byte, error)
+}
+
+// fetch dereferences a remote object, returning nil if it's not possible or it failed
+func (o Options) fetch(url string) []byte {
+	if o.Fetch == nil || url == "" {
+		return nil
+	}
+	data, err := o.Fetch(url)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	return data
+}
+
+// maxContent returns the maximum length of the content, or a negative value if there is no limit
+func (o Options) maxContent() int {
+	if o.MaxContent == 0 {
+		return DefaultMaxContent
+	}
+	return o.MaxContent
+}
+
+// width returns the width of the summary, detecting the width of the terminal if it isn't set
+func (o Options) width() int {
+	if o.Width > 0 {
+		return o.Width
+	}
+	if width, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultWidth
+}
+
+// nested returns the options for a summary nested in a box, which is a bit narrower
+func (o Options) nested() Options {
+	o.Width = max(o.width()-boxIndent, 20)
+	return o
+}
+
+// theme returns the theme used for the summary
+func (o Options) theme() Theme {
+	if o.Theme == nil {
+		return DefaultTheme
+	}
+	return *o.Theme
+}
+
+// timezone returns the timezone dates are shown in
+func (o Options) timezone() *time.Location {
+	if o.Timezone == nil {
+		return time.Local
+	}
+	return o.Timezone
+}
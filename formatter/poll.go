@@ -0,0 +1,91 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Width of the poll result bars in terminal columns
+const pollBarWidth = 20
+
+// formatPoll formats the options and results of a poll (Question type)
+func formatPoll(jsonStr []byte, parts []string, opts Options, bold, green, yellow, red func(a ...interface{}) string) []string {
+	// Include endTime for Question type
+	endTime := gjson.GetBytes(jsonStr, "endTime").String()
+	if endTime != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("End Time"), yellow(formatDate(endTime, opts))))
+	}
+
+	// Include options (oneOf/anyOf) for Question type
+	pollType := "single choice"
+	options := gjson.GetBytes(jsonStr, "oneOf").Array()
+	if len(options) == 0 {
+		options = gjson.GetBytes(jsonStr, "anyOf").Array()
+		pollType = "multiple choice"
+	}
+	if len(options) == 0 {
+		return parts
+	}
+
+	status := green("open")
+	if isPollClosed(jsonStr, endTime) {
+		status = red("closed")
+	}
+	parts = append(parts, fmt.Sprintf("%s: %s, %s", bold("Poll"), pollType, status))
+
+	var totalVotes int64
+	for _, opt := range options {
+		totalVotes += optionVotes(opt)
+	}
+
+	// For multiple choice polls the percentages are relative to the number of voters (like Mastodon)
+	votersCount := gjson.GetBytes(jsonStr, "votersCount")
+	total := totalVotes
+	if pollType == "multiple choice" && votersCount.Int() > 0 {
+		total = votersCount.Int()
+	}
+
+	parts = append(parts, fmt.Sprintf("%s:", bold("Poll Options")))
+	for i, opt := range options {
+		name := opt.Get("name").String()
+		votes := optionVotes(opt)
+		percentage := 0.0
+		if total > 0 {
+			percentage = float64(votes) / float64(total) * 100
+		}
+		// Voters of multiple choice polls might be counted wrong (e.g. more votes than voters), keep the bar within its width
+		filled := min(max(int(percentage/100*pollBarWidth+0.5), 0), pollBarWidth)
+		bar := strings.Repeat("█", filled) + strings.Repeat("░", pollBarWidth-filled)
+		parts = append(parts, fmt.Sprintf("  %d. %s", i+1, name))
+		parts = append(parts, fmt.Sprintf("     %s %5.1f%% (%d votes)", green(bar), percentage, votes))
+	}
+
+	if votersCount.Exists() {
+		parts = append(parts, fmt.Sprintf("%s: %d", bold("Voters"), votersCount.Int()))
+	}
+	parts = append(parts, fmt.Sprintf("%s: %d", bold("Total Votes"), totalVotes))
+
+	return parts
+}
+
+// optionVotes returns the number of votes of a poll option, ignoring invalid (negative) counts
+func optionVotes(option gjson.Result) int64 {
+	return max(option.Get("replies.totalItems").Int(), 0)
+}
+
+// isPollClosed returns true if the poll is closed, either explicitly (closed) or because its end time has passed
+func isPollClosed(jsonStr []byte, endTime string) bool {
+	// closed is normally the date and time the poll was closed, but might be a boolean
+	if closed := gjson.GetBytes(jsonStr, "closed"); closed.Exists() {
+		if closed.Type == gjson.String || closed.Bool() {
+			return true
+		}
+	}
+	if t, ok := parseDate(endTime); ok {
+		return t.Before(time.Now())
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatPoll(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{
+			name: "single choice",
+			json: `{"type":"Question","oneOf":[{"name":"a","replies":{"totalItems":3}},{"name":"b","replies":{"totalItems":1}}]}`,
+			want: []string{"███████████████░░░░░  75.0% (3 votes)", "█████░░░░░░░░░░░░░░░  25.0% (1 votes)", "Total Votes: 4"},
+		},
+		{
+			name: "no votes",
+			json: `{"type":"Question","oneOf":[{"name":"a"}]}`,
+			want: []string{"░░░░░░░░░░░░░░░░░░░░   0.0% (0 votes)", "Total Votes: 0"},
+		},
+		{
+			name: "more votes than voters",
+			json: `{"type":"Question","votersCount":1,"anyOf":[{"name":"a","replies":{"totalItems":5}}]}`,
+			want: []string{"████████████████████ 500.0% (5 votes)", "Voters: 1"},
+		},
+		{
+			name: "negative votes",
+			json: `{"type":"Question","oneOf":[{"name":"a","replies":{"totalItems":-5}},{"name":"b","replies":{"totalItems":2}}]}`,
+			want: []string{"░░░░░░░░░░░░░░░░░░░░   0.0% (0 votes)", "████████████████████ 100.0% (2 votes)", "Total Votes: 2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output := strings.Join(formatPoll([]byte(test.json), nil, Options{}, fmt.Sprint, fmt.Sprint, fmt.Sprint, fmt.Sprint), "\n")
+			for _, want := range test.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("formatPoll() =\n%s\nwant it to contain %q", output, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizePollDoesNotPanic(t *testing.T) {
+	Summarize([]byte(`{"type":"Question","votersCount":1,"anyOf":[{"name":"a","replies":{"totalItems":5}}]}`), Options{})
+	Summarize([]byte(`{"type":"Question","oneOf":[{"name":"a","replies":{"totalItems":-1}}]}`), Options{})
+}
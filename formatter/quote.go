@@ -0,0 +1,103 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// quoteProperties are the properties used by software to link the quoted post:
+// FEP-044f/Mastodon (quote), Misskey (_misskey_quote), Fedibird/Akkoma (quoteUri, quoteUrl)
+var quoteProperties = []string{"quote", "_misskey_quote", "quoteUri", "quoteUrl"}
+
+// getQuoteURLs returns the URLs of the quoted posts, found in the quote properties and in FEP-e232 object links
+func getQuoteURLs(object gjson.Result) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url != "" && !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+
+	for _, property := range quoteProperties {
+		add(firstString(object.Get(property)))
+	}
+	for _, tag := range resultArray(object.Get("tag")) {
+		if tag.Get("type").String() == "Link" && isActivityStreamsMediaType(tag.Get("mediaType").String()) {
+			add(tag.Get("href").String())
+		}
+	}
+	return urls
+}
+
+// isActivityStreamsMediaType returns true if the media type refers to an ActivityStreams object
+func isActivityStreamsMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "application/activity+json") ||
+		(strings.HasPrefix(mediaType, "application/ld+json") && strings.Contains(mediaType, "https://www.w3.org/ns/activitystreams"))
+}
+
+// formatQuotes formats the quoted posts of an object, including the quote authorization (Mastodon).
+// When resolving quotes is enabled, the quoted post is rendered inline in a box.
+func formatQuotes(object gjson.Result, parts []string, opts Options, bold, green, red func(a ...interface{}) string) []string {
+	for _, quoteURL := range getQuoteURLs(object) {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Quote Of"), green(quoteURL)))
+		if !opts.ResolveQuotes {
+			continue
+		}
+		quoted := opts.fetch(quoteURL)
+		if quoted == nil {
+			parts = append(parts, fmt.Sprintf("  %s", red("Quoted post could not be fetched")))
+			continue
+		}
+		// Don't follow quotes of quotes
+		nestedOpts := opts.nested()
+		nestedOpts.ResolveQuotes = false
+		parts = append(parts, boxed(createSummary(quoted, nestedOpts)))
+	}
+
+	if authorization := firstString(object.Get("quoteAuthorization")); authorization != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Quote Authorization"), green(authorization)))
+		if opts.ResolveQuotes {
+			parts = formatQuoteAuthorization(authorization, object.Get("id").String(), parts, opts, green, red)
+		}
+	}
+
+	if canQuote := object.Get("interactionPolicy.canQuote"); canQuote.Exists() {
+		cache := make(map[string]string)
+		if automatic := resultStrings(canQuote.Get("automaticApproval")); len(automatic) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Can Quote (automatic approval)"), formatList(describeRecipients(automatic, opts, cache))))
+		}
+		if manual := resultStrings(canQuote.Get("manualApproval")); len(manual) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Can Quote (manual approval)"), formatList(describeRecipients(manual, opts, cache))))
+		}
+	}
+
+	return parts
+}
+
+// formatQuoteAuthorization fetches the QuoteAuthorization, and checks whether it authorizes this object
+func formatQuoteAuthorization(authorizationURL, objectID string, parts []string, opts Options, green, red func(a ...interface{}) string) []string {
+	authorization := opts.fetch(authorizationURL)
+	if authorization == nil {
+		return append(parts, fmt.Sprintf("  %s", red("Quote authorization could not be fetched")))
+	}
+	interactingObject := firstString(gjson.GetBytes(authorization, "interactingObject"))
+	if interactingObject != "" && interactingObject == objectID {
+		return append(parts, fmt.Sprintf("  %s", green("✔ quote is authorized by the quoted author")))
+	}
+	return append(parts, fmt.Sprintf("  %s", red("✘ authorization does not match this post")))
+}
+
+// boxed draws a box around the (multi-line) text, used for nesting summaries
+func boxed(text string) string {
+	var b strings.Builder
+	b.WriteString("  ┌────\n")
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		b.WriteString("  │ " + line + "\n")
+	}
+	b.WriteString("  └────")
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package formatter
+
+import (
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// Colors are the color functions renderers use to highlight parts of the summary.
+// They are named after their role, the actual colors depend on the theme (see Theme).
+type Colors struct {
+	// Label is used for the property names
+	Label func(a ...interface{}) string
+	// Type is used for object types and names
+	Type func(a ...interface{}) string
+	// URL is used for links, and for positive values (e.g. open registrations)
+	URL func(a ...interface{}) string
+	// Date is used for dates, and for other highlighted values (e.g. languages)
+	Date func(a ...interface{}) string
+	// Warning is used for warnings and negative values (e.g. missing alt text)
+	Warning func(a ...interface{}) string
+	// Username is used for the username of actors
+	Username func(a ...interface{}) string
+}
+
+// Renderer renders the type specific part of the summary of an ActivityPub object.
+// It appends its lines to parts, which already contain the type header and the id.
+type Renderer interface {
+	Render(jsonStr []byte, parts []string, opts Options, colors Colors) []string
+}
+
+// RendererFunc is an adapter to allow the use of ordinary functions as Renderer
+type RendererFunc func(jsonStr []byte, parts []string, opts Options, colors Colors) []string
+
+// Render calls f(jsonStr, parts, opts, colors)
+func (f RendererFunc) Render(jsonStr []byte, parts []string, opts Options, colors Colors) []string {
+	return f(jsonStr, parts, opts, colors)
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = make(map[string]Renderer)
+	fallback    Renderer
+)
+
+// Register registers the renderer for the given object types, replacing the renderer
+// (built-in or not) that was registered for these types before
+func Register(renderer Renderer, objectTypes ...string) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	for _, objectType := range objectTypes {
+		renderers[objectType] = renderer
+	}
+}
+
+// RegisterFallback registers the renderer used for object types without a registered renderer
+func RegisterFallback(renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	fallback = renderer
+}
+
+// LookupRenderer returns the renderer registered for the object type, or nil if there is none
+func LookupRenderer(objectType string) Renderer {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	return renderers[objectType]
+}
+
+// rendererFor returns the renderer for the type of the object, falling back to the fallback renderer
+func rendererFor(objectType gjson.Result) Renderer {
+	for _, t := range resultArray(objectType) {
+		if renderer := LookupRenderer(t.String()); renderer != nil {
+			return renderer
+		}
+	}
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	return fallback
+}
+
+func init() {
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatActor(jsonStr, parts, opts, c.Label, c.Type, c.URL, c.Warning, c.Date, c.Username)
+	}), "Person", "Application", "Organization", "Service")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		parts = formatActor(jsonStr, parts, opts, c.Label, c.Type, c.URL, c.Warning, c.Date, c.Username)
+		return formatGroup(jsonStr, parts, opts, c.Label, c.URL, c.Date, c.Warning)
+	}), "Group")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatContent(jsonStr, parts, opts, c.Label, c.URL, c.Date, c.Warning)
+	}), "Note", "Article", "Page", "Question")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatActivity(jsonStr, parts, opts, c.Label, c.URL, c.Date, c.Warning)
+	}), "Create", "Update", "Delete", "Follow", "Add", "Remove", "Like", "Block", "Announce", "Move",
+		"Undo", "Accept", "Reject", "TentativeAccept", "TentativeReject", "Flag", "EmojiReact", "Dislike",
+		"Join", "Leave", "Invite")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatCollection(jsonStr, parts, opts, c.Label, c.URL, c.Date)
+	}), "Collection", "OrderedCollection", "CollectionPage", "OrderedCollectionPage")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatMedia(jsonStr, parts, opts, c.Label, c.URL, c.Date, c.Warning)
+	}), "Image", "Audio", "Video", "Document")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatEvent(jsonStr, parts, opts, c.Label, c.URL, c.Date, c.Warning)
+	}), "Event")
+	Register(RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return formatTombstone(jsonStr, parts, opts, c.Label, c.URL, c.Date)
+	}), "Tombstone")
+	RegisterFallback(RendererFunc(formatGeneric))
+}
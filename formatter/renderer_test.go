@@ -0,0 +1,89 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tidwall/gjson"
+)
+
+// markerRenderer returns a renderer which adds the marker to the summary
+func markerRenderer(marker string) Renderer {
+	return RendererFunc(func(jsonStr []byte, parts []string, opts Options, c Colors) []string {
+		return append(parts, marker)
+	})
+}
+
+// restoreRenderers restores the registered renderers and the fallback after the test
+func restoreRenderers(t *testing.T, objectTypes ...string) {
+	previous := make(map[string]Renderer)
+	for _, objectType := range objectTypes {
+		previous[objectType] = LookupRenderer(objectType)
+	}
+	previousFallback := fallback
+	t.Cleanup(func() {
+		renderersMu.Lock()
+		defer renderersMu.Unlock()
+		for objectType, renderer := range previous {
+			if renderer == nil {
+				delete(renderers, objectType)
+			} else {
+				renderers[objectType] = renderer
+			}
+		}
+		fallback = previousFallback
+	})
+}
+
+func TestRegister(t *testing.T) {
+	restoreRenderers(t, "ChatMessage", "Track", "Note")
+
+	if LookupRenderer("ChatMessage") != nil {
+		t.Fatalf("LookupRenderer(ChatMessage) returned a renderer before registering one")
+	}
+	Register(markerRenderer("custom renderer"), "ChatMessage", "Track")
+	for _, objectType := range []string{"ChatMessage", "Track"} {
+		if LookupRenderer(objectType) == nil {
+			t.Errorf("LookupRenderer(%s) = nil after registering it", objectType)
+		}
+		summary := Summarize([]byte(`{"type":"`+objectType+`","id":"https://a.example/1"}`), Options{})
+		if !strings.Contains(summary, "custom renderer") {
+			t.Errorf("summary of %s doesn't use the registered renderer:\n%s", objectType, summary)
+		}
+	}
+
+	// Built-in renderers can be overridden
+	Register(markerRenderer("overridden note"), "Note")
+	summary := Summarize([]byte(`{"type":"Note","id":"https://a.example/1","content":"hello"}`), Options{})
+	if !strings.Contains(summary, "overridden note") || strings.Contains(summary, "hello") {
+		t.Errorf("summary of a Note doesn't use the overriding renderer:\n%s", summary)
+	}
+}
+
+func TestRendererFor(t *testing.T) {
+	restoreRenderers(t, "ChatMessage", "Track")
+	Register(markerRenderer("chat"), "ChatMessage")
+	Register(markerRenderer("track"), "Track")
+	RegisterFallback(markerRenderer("fallback"))
+
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"registered type", `{"type":"Track"}`, "track"},
+		{"first registered type of an array", `{"type":["Unknown","ChatMessage","Track"]}`, "chat"},
+		{"unknown type", `{"type":"Unknown"}`, "fallback"},
+		{"unknown types", `{"type":["Unknown","Other"]}`, "fallback"},
+		{"no type", `{}`, "fallback"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			object := []byte(test.json)
+			parts := rendererFor(gjson.GetBytes(object, "type")).Render(object, nil, Options{}, DefaultTheme.colors())
+			if len(parts) != 1 || parts[0] != test.want {
+				t.Errorf("rendererFor(%s) rendered %q, want %q", test.json, parts, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+// allowedTags are the HTML elements kept when sanitizing content, roughly the ones Mastodon allows
+var allowedTags = map[string]bool{
+	"p": true, "br": true, "span": true, "a": true, "del": true, "s": true, "pre": true, "code": true,
+	"b": true, "strong": true, "i": true, "em": true, "u": true, "blockquote": true,
+	"ul": true, "ol": true, "li": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// droppedTags are the HTML elements that are removed including their content
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "template": true,
+}
+
+// sanitizeHTML removes all elements and attributes from the HTML fragment that are not known to be safe,
+// so remote content can be embedded in an exported document
+func sanitizeHTML(fragment string) string {
+	var b strings.Builder
+	tokenizer := nethtml.NewTokenizer(strings.NewReader(fragment))
+	dropping := 0
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == nethtml.ErrorToken {
+			return b.String()
+		}
+		token := tokenizer.Token()
+		switch tokenType {
+		case nethtml.TextToken:
+			if dropping == 0 {
+				b.WriteString(html.EscapeString(token.Data))
+			}
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken:
+			if droppedTags[token.Data] {
+				if tokenType == nethtml.StartTagToken {
+					dropping++
+				}
+				continue
+			}
+			if dropping > 0 || !allowedTags[token.Data] {
+				continue
+			}
+			b.WriteString("<" + token.Data)
+			if token.Data == "a" {
+				for _, attr := range token.Attr {
+					if attr.Key == "href" && isSafeURL(attr.Val) {
+						b.WriteString(` href="` + html.EscapeString(attr.Val) + `" rel="nofollow noopener"`)
+					}
+				}
+			}
+			b.WriteString(">")
+		case nethtml.EndTagToken:
+			if droppedTags[token.Data] {
+				if dropping > 0 {
+					dropping--
+				}
+				continue
+			}
+			if dropping == 0 && allowedTags[token.Data] && token.Data != "br" {
+				b.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+// isSafeURL returns true if the URL is an absolute http(s) URL
+func isSafeURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,50 @@
+package formatter
+
+import "testing"
+
+func TestSanitizeHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"allowed tags", `<p>Hello <strong>world</strong><br>bye</p>`, `<p>Hello <strong>world</strong><br>bye</p>`},
+		{"attributes are dropped", `<p class="x" onclick="alert(1)">Hi</p>`, `<p>Hi</p>`},
+		{"safe link", `<a href="https://example.com/?a=1&amp;b=2" class="mention">Link</a>`, `<a href="https://example.com/?a=1&amp;b=2" rel="nofollow noopener">Link</a>`},
+		{"javascript link", `<a href="javascript:alert(1)">Link</a>`, `<a>Link</a>`},
+		{"relative link", `<a href="/users/alice">Link</a>`, `<a>Link</a>`},
+		{"script is dropped with content", `<p>a<script>alert(1)</script>b</p>`, `<p>ab</p>`},
+		{"nested dropped tags", `<iframe><object>x</object>y</iframe>z`, `z`},
+		{"self-closing dropped tag", `<embed src="x"/>text`, `text`},
+		{"unknown tags keep their text", `<div><img src="x" onerror="alert(1)">text</div>`, `text`},
+		{"text is escaped", `&lt;script&gt;alert(1)&lt;/script&gt;`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{"stray end tags", `</div></p>text`, `</p>text`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := sanitizeHTML(test.html); got != test.want {
+				t.Errorf("sanitizeHTML(%q) = %q, want %q", test.html, got, test.want)
+			}
+		})
+	}
+}
+
+func TestIsSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/path", true},
+		{"http://example.com", true},
+		{"javascript:alert(1)", false},
+		{"data:text/html,<script>alert(1)</script>", false},
+		{"//example.com/path", false},
+		{"/relative", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if got := isSafeURL(test.url); got != test.want {
+			t.Errorf("isSafeURL(%q) = %v, want %v", test.url, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// hiddenContent replaces content behind a content warning in the raw JSON
+const hiddenContent = "(hidden behind the content warning, use --show-sensitive to show it)"
+
+// contentWarning returns the content warning (CW) of a post, which Mastodon and others put in the summary.
+// The summary is a content warning when the object is marked sensitive (e.g. an Article from Lemmy or Misskey),
+// notes and questions use the summary only for content warnings.
+func contentWarning(jsonStr []byte, opts Options) string {
+	cw := singleLine(htmlToText(getLocalized(jsonStr, "summary", opts).Value))
+	if cw == "" {
+		return ""
+	}
+	if gjson.GetBytes(jsonStr, "sensitive").Bool() {
+		return cw
+	}
+	switch gjson.GetBytes(jsonStr, "type").String() {
+	case "Note", "Question":
+		return cw
+	}
+	return ""
+}
+
+// HideSensitiveContent replaces the content of the object (and an embedded object) when it is behind
+// a content warning, unless showing sensitive content is enabled. It's used for the raw JSON that's shown.
+// Only the content values are replaced, everything else (e.g. the order of the keys) is left untouched.
+func HideSensitiveContent(jsonData []byte, opts Options) []byte {
+	if opts.ShowSensitive {
+		return jsonData
+	}
+	jsonData = hideContent(jsonData, "", opts)
+	if object := gjson.GetBytes(jsonData, "object"); object.IsObject() {
+		jsonData = hideContent(jsonData, "object.", opts)
+	}
+	return jsonData
+}
+
+// hideContent replaces all content properties of the object at the path prefix when it has a content warning
+func hideContent(jsonData []byte, prefix string, opts Options) []byte {
+	object := gjson.ParseBytes(jsonData)
+	if prefix != "" {
+		object = gjson.GetBytes(jsonData, strings.TrimSuffix(prefix, "."))
+	}
+	if contentWarning([]byte(object.Raw), opts) == "" {
+		return jsonData
+	}
+
+	var paths []string
+	for _, property := range []string{"content", "_misskey_content", "source.content"} {
+		if object.Get(property).Exists() {
+			paths = append(paths, property)
+		}
+	}
+	object.Get("contentMap").ForEach(func(language, _ gjson.Result) bool {
+		paths = append(paths, "contentMap."+escapePath(language.String()))
+		return true
+	})
+	for _, path := range paths {
+		if redacted, err := sjson.SetBytes(jsonData, prefix+path, hiddenContent); err == nil {
+			jsonData = redacted
+		}
+	}
+	return jsonData
+}
+
+// escapePath escapes the characters of a key that have a special meaning in gjson and sjson paths
+func escapePath(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if strings.ContainsRune(`.*?|#@!=<>%\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// formatContentBody formats the content of a post. Like clients do, content with a content
+// warning is folded behind the warning, unless showing sensitive content is enabled.
+func formatContentBody(jsonStr []byte, parts []string, opts Options, bold, yellow, red func(a ...interface{}) string) []string {
+	cw := contentWarning(jsonStr, opts)
+	if cw != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", red(bold("Content Warning")), red(cw)))
+	}
+
+	content := getLocalized(jsonStr, "content", opts)
+	if content.Value != "" {
+		md := replaceEmojiShortcodes(htmlToMarkdown(content.Value), gjson.GetBytes(jsonStr, "tag"))
+		if cw != "" && !opts.ShowSensitive {
+			hidden := fmt.Sprintf("hidden behind the content warning (%d characters), use --show-sensitive to show it", uniseg.GraphemeClusterCount(md))
+			parts = append(parts, fmt.Sprintf("%s: %s", bold("Content"), yellow(hidden)))
+		} else {
+			// Truncate the content if its too big.
+			md = truncate(md, opts.maxContent())
+			parts = append(parts, fmt.Sprintf("%s:\n%s", bold("Content"), renderMarkdown(md, opts)))
+		}
+	}
+	return formatLanguages(content, parts, "Content Languages", bold, yellow)
+}
@@ -0,0 +1,65 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContentWarning(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"note with summary", `{"type":"Note","summary":"spoilers","content":"x"}`, "spoilers"},
+		{"question with summary", `{"type":"Question","summary":"<p>politics</p>"}`, "politics"},
+		{"sensitive article", `{"type":"Article","summary":"gore","sensitive":true}`, "gore"},
+		{"sensitive page", `{"type":"Page","summaryMap":{"en":"nsfw"},"sensitive":true}`, "nsfw"},
+		{"article excerpt", `{"type":"Article","summary":"An introduction"}`, ""},
+		{"only sensitive media", `{"type":"Note","sensitive":true,"summary":null}`, ""},
+		{"actor bio", `{"type":"Person","summary":"Hi, I'm Alice"}`, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := contentWarning([]byte(test.json), Options{}); got != test.want {
+				t.Errorf("contentWarning(%s) = %q, want %q", test.json, got, test.want)
+			}
+		})
+	}
+}
+
+func TestHideSensitiveContent(t *testing.T) {
+	note := `{"type":"Create","object":{"type":"Note","summary":"cw","content":"secret","contentMap":{"en":"secret"},"source":{"content":"secret"}}}`
+	hidden := string(HideSensitiveContent([]byte(note), Options{}))
+	if strings.Contains(hidden, "secret") || !strings.Contains(hidden, hiddenContent) {
+		t.Errorf("HideSensitiveContent() = %s, want the content to be hidden", hidden)
+	}
+	if shown := string(HideSensitiveContent([]byte(note), Options{ShowSensitive: true})); shown != note {
+		t.Errorf("HideSensitiveContent() with ShowSensitive = %s, want %s", shown, note)
+	}
+	// Only the content is replaced, the keys keep their order and other values aren't re-encoded
+	post := `{"id":"https://example.com/notes/1?a=1&b=<2>","type":"Note","summary":"cw","likes":12345678901234567890,"contentMap":{"en.US":"secret","nl":"geheim"},"content":"secret"}`
+	want := `{"id":"https://example.com/notes/1?a=1&b=<2>","type":"Note","summary":"cw","likes":12345678901234567890,"contentMap":{"en.US":"` + hiddenContent + `","nl":"` + hiddenContent + `"},"content":"` + hiddenContent + `"}`
+	if got := string(HideSensitiveContent([]byte(post), Options{})); got != want {
+		t.Errorf("HideSensitiveContent(%s) = %s, want %s", post, got, want)
+	}
+	plain := `{"type":"Note","content":"hello"}`
+	if got := string(HideSensitiveContent([]byte(plain), Options{})); got != plain {
+		t.Errorf("HideSensitiveContent(%s) = %s, want it unchanged", plain, got)
+	}
+}
+
+func TestFormatMarkdownFoldsContentWarning(t *testing.T) {
+	note := `{"type":"Note","id":"https://example.com/notes/1","summary":"spoilers","sensitive":true,"content":"<p>secret</p>"}`
+	markdown, err := FormatMarkdown([]byte(note), Options{})
+	if err != nil {
+		t.Fatalf("FormatMarkdown() returned error: %v", err)
+	}
+	if strings.Contains(markdown, "secret") || !strings.Contains(markdown, "**Content warning:** spoilers") {
+		t.Errorf("FormatMarkdown() doesn't fold the content:\n%s", markdown)
+	}
+	markdown, _ = FormatMarkdown([]byte(note), Options{ShowSensitive: true})
+	if !strings.Contains(markdown, "**Content warning:** spoilers\n\nsecret") {
+		t.Errorf("FormatMarkdown() with ShowSensitive doesn't show the content:\n%s", markdown)
+	}
+}
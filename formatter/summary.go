@@ -0,0 +1,89 @@
+package formatter
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+// Summary is the structured summary of an ActivityPub object (or nodeinfo), as used by the output templates
+type Summary struct {
+	// Type is the (first) type of the object, Types contains all of them
+	Type  string
+	Types []string
+	ID    string
+	URL   string
+	// Name is the display name (of an actor) or title, Username the preferredUsername of an actor
+	Name     string
+	Username string
+	// Summary and Content are HTML, in the preferred language (if available)
+	Summary string
+	Content string
+	// ContentWarning is the content warning (as text) of a post, when it has one
+	ContentWarning string
+	Language       string
+	Author         string
+	Published      string
+	Updated        string
+	Sensitive      bool
+	Icon           string
+	// Attachments are the media attachments (not the profile fields of actors)
+	Attachments []SummaryAttachment
+	// Raw is the original JSON of the object
+	Raw string
+}
+
+// SummaryAttachment is an attachment of a Summary
+type SummaryAttachment struct {
+	Type      string
+	MediaType string
+	URL       string
+	Name      string
+}
+
+// NewSummary creates the structured summary of the ActivityPub object
+func NewSummary(jsonData []byte, opts Options) Summary {
+	types := resultStrings(gjson.GetBytes(jsonData, "type"))
+	summary := Summary{
+		Types:     types,
+		ID:        gjson.GetBytes(jsonData, "id").String(),
+		URL:       firstString(gjson.GetBytes(jsonData, "url")),
+		Name:      getLocalized(jsonData, "name", opts).Value,
+		Username:  gjson.GetBytes(jsonData, "preferredUsername").String(),
+		Summary:   getLocalized(jsonData, "summary", opts).Value,
+		Published: gjson.GetBytes(jsonData, "published").String(),
+		Updated:   gjson.GetBytes(jsonData, "updated").String(),
+		Sensitive: gjson.GetBytes(jsonData, "sensitive").Bool(),
+		Icon:      imageURL(gjson.GetBytes(jsonData, "icon")),
+		Raw:       string(jsonData),
+	}
+	if len(types) > 0 {
+		summary.Type = types[0]
+	}
+
+	content := getLocalized(jsonData, "content", opts)
+	summary.Content = content.Value
+	summary.ContentWarning = contentWarning(jsonData, opts)
+	summary.Language = content.Language
+
+	// Activities have an actor instead of an author
+	summary.Author = firstString(gjson.GetBytes(jsonData, "attributedTo"))
+	if summary.Author == "" {
+		summary.Author = firstString(gjson.GetBytes(jsonData, "actor"))
+	}
+
+	for _, attachment := range resultArray(gjson.GetBytes(jsonData, "attachment")) {
+		if attachment.Get("type").String() == "PropertyValue" {
+			continue
+		}
+		url := linkURL(attachment.Get("url"))
+		if url == "" {
+			url = attachment.Get("href").String()
+		}
+		summary.Attachments = append(summary.Attachments, SummaryAttachment{
+			Type:      attachment.Get("type").String(),
+			MediaType: attachment.Get("mediaType").String(),
+			URL:       url,
+			Name:      attachment.Get("name").String(),
+		})
+	}
+	return summary
+}
@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// resultArray returns the elements of a result, which can either be a single value or an array
+func resultArray(result gjson.Result) []gjson.Result {
+	if !result.Exists() {
+		return nil
+	}
+	if result.IsArray() {
+		return result.Array()
+	}
+	return []gjson.Result{result}
+}
+
+// formatTags formats the mentions, hashtags and custom emojis found in the tag array
+func formatTags(tags gjson.Result, parts []string, opts Options, bold, green func(a ...interface{}) string) []string {
+	var mentions, hashtags, emojis []string
+	for _, tag := range resultArray(tags) {
+		name := tag.Get("name").String()
+		href := tag.Get("href").String()
+		switch tag.Get("type").String() {
+		case "Mention":
+			mentions = append(mentions, describeMention(name, href, opts))
+		case "Hashtag":
+			if !strings.HasPrefix(name, "#") {
+				name = "#" + name
+			}
+			if href != "" {
+				name += fmt.Sprintf(" (%s)", green(href))
+			}
+			hashtags = append(hashtags, name)
+		case "Emoji":
+			emoji := name
+			if iconURL := tag.Get("icon.url").String(); iconURL != "" {
+				emoji += fmt.Sprintf(" (%s)", green(iconURL))
+			}
+			emojis = append(emojis, emoji)
+		}
+	}
+
+	if len(mentions) > 0 {
+		parts = append(parts, fmt.Sprintf("%s:", bold("Mentions")))
+		for _, mention := range mentions {
+			parts = append(parts, fmt.Sprintf("  - %s", mention))
+		}
+	}
+	if len(hashtags) > 0 {
+		parts = append(parts, fmt.Sprintf("%s:", bold("Hashtags")))
+		for _, hashtag := range hashtags {
+			parts = append(parts, fmt.Sprintf("  - %s", hashtag))
+		}
+	}
+	if len(emojis) > 0 {
+		parts = append(parts, fmt.Sprintf("%s:", bold("Custom Emojis")))
+		for _, emoji := range emojis {
+			parts = append(parts, fmt.Sprintf("  - %s", emoji))
+		}
+	}
+
+	return parts
+}
+
+// describeMention returns the handle of a mentioned actor, resolving the actor when the options allow it
+func describeMention(name, href string, opts Options) string {
+	if opts.ResolveMentions {
+		if actor := opts.fetch(href); actor != nil {
+			return fmt.Sprintf("%s: %s", actorHandle(actor, href), href)
+		}
+	}
+	if name == "" {
+		return href
+	}
+	// Some software (like Mastodon) leaves out the domain for local mentions
+	if strings.Count(name, "@") == 1 && href != "" {
+		if host := urlHost(href); host != "" {
+			name += "@" + host
+		}
+	}
+	if href == "" {
+		return name
+	}
+	return fmt.Sprintf("%s: %s", name, href)
+}
+
+// replaceEmojiShortcodes replaces the :shortcode: of custom emojis with a marker,
+// so they stand out in the rendered text
+func replaceEmojiShortcodes(text string, tags gjson.Result) string {
+	for _, tag := range resultArray(tags) {
+		if tag.Get("type").String() != "Emoji" {
+			continue
+		}
+		shortcode := tag.Get("name").String()
+		if shortcode == "" {
+			continue
+		}
+		if !strings.HasPrefix(shortcode, ":") {
+			shortcode = ":" + shortcode + ":"
+		}
+		text = strings.ReplaceAll(text, shortcode, "[emoji "+shortcode+"]")
+	}
+	return text
+}
@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// ExecuteTemplate executes the Go template over the structured summary of the object.
+// Besides the Summary fields, the raw JSON can be queried using {{gjson "path"}}.
+// Content behind a content warning is hidden, unless showing sensitive content is enabled.
+func ExecuteTemplate(text string, jsonData []byte, opts Options) (string, error) {
+	jsonData = HideSensitiveContent(jsonData, opts)
+	funcs := template.FuncMap{
+		"gjson": func(path string) string {
+			return gjson.GetBytes(jsonData, path).String()
+		},
+		"htmlToMarkdown": htmlToMarkdown,
+		"htmlToText":     htmlToText,
+		"formatDate": func(date string) string {
+			return formatAbsoluteDate(date, opts)
+		},
+		"relativeTime": func(date string) string {
+			if t, ok := parseDate(date); ok {
+				return relativeTime(t, time.Now())
+			}
+			return date
+		},
+		"truncate": func(length int, text string) string {
+			return truncate(text, length)
+		},
+	}
+	tmpl, err := template.New("output").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %v", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, NewSummary(jsonData, opts)); err != nil {
+		return "", fmt.Errorf("error executing template: %v", err)
+	}
+	return b.String(), nil
+}
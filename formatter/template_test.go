@@ -0,0 +1,45 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExecuteTemplate(t *testing.T) {
+	note := `{"type":"Note","id":"https://a.example/notes/1","attributedTo":"https://a.example/users/alice","content":"<p>Hello <b>world</b></p>"}`
+	warned := `{"type":"Note","summary":"spoilers","sensitive":true,"content":"<p>secret</p>"}`
+	tests := []struct {
+		name     string
+		template string
+		json     string
+		opts     Options
+		want     string
+	}{
+		{"fields", "{{.Type}} {{.Author}}", note, Options{}, "Note https://a.example/users/alice"},
+		{"gjson", `{{gjson "id"}}`, note, Options{}, "https://a.example/notes/1"},
+		{"html to text", "{{htmlToText .Content}}", note, Options{}, "Hello world"},
+		{"truncate", "{{truncate 8 (htmlToText .Content)}}", note, Options{}, "Hello..."},
+		{"content warning", "{{.ContentWarning}}: {{.Content}}", warned, Options{}, "spoilers: " + hiddenContent},
+		{"content warning in the raw JSON", `{{gjson "content"}}`, warned, Options{}, hiddenContent},
+		{"shown sensitive content", "{{.Content}}", warned, Options{ShowSensitive: true}, "<p>secret</p>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ExecuteTemplate(test.template, []byte(test.json), test.opts)
+			if err != nil {
+				t.Fatalf("ExecuteTemplate() returned error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("ExecuteTemplate(%q) = %q, want %q", test.template, got, test.want)
+			}
+		})
+	}
+
+	summary, _ := ExecuteTemplate("{{.Raw}}", []byte(warned), Options{})
+	if strings.Contains(summary, "secret") {
+		t.Errorf("ExecuteTemplate() exposes hidden content in .Raw: %s", summary)
+	}
+	if _, err := ExecuteTemplate("{{.Unknown}}", []byte(note), Options{}); err == nil {
+		t.Errorf("ExecuteTemplate() with an unknown field didn't return an error")
+	}
+}
@@ -0,0 +1,196 @@
+package formatter
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Theme maps the semantic roles in the summary to colors. Every role is a space separated list of
+// attributes (bold, italic, underline, etc.) and colors (red, hi-red, bg-red, etc.), empty means plain text.
+type Theme struct {
+	// Label is used for the property names
+	Label string
+	// Type is used for object types and names
+	Type string
+	// URL is used for links, and for positive values (e.g. open registrations)
+	URL string
+	// Date is used for dates, and for other highlighted values (e.g. languages)
+	Date string
+	// Warning is used for warnings and negative values (e.g. missing alt text)
+	Warning string
+	// Username is used for the username of actors
+	Username string
+}
+
+// Built-in themes
+var (
+	DefaultTheme = Theme{
+		Label:    "bold",
+		Type:     "cyan",
+		URL:      "green",
+		Date:     "yellow",
+		Warning:  "red",
+		Username: "red",
+	}
+	// HighContrastTheme uses bright colors and underlined links, for dark terminals
+	HighContrastTheme = Theme{
+		Label:    "bold hi-white",
+		Type:     "bold hi-cyan",
+		URL:      "underline hi-green",
+		Date:     "hi-yellow",
+		Warning:  "bold reverse hi-red",
+		Username: "bold hi-magenta",
+	}
+	// MonochromeTheme doesn't use colors, only text attributes
+	MonochromeTheme = Theme{
+		Label:    "bold",
+		URL:      "underline",
+		Warning:  "bold",
+		Username: "bold",
+	}
+)
+
+// BuiltinTheme returns the built-in theme with the given name (default, high-contrast or monochrome)
+func BuiltinTheme(name string) (Theme, bool) {
+	switch name {
+	case "default":
+		return DefaultTheme, true
+	case "high-contrast":
+		return HighContrastTheme, true
+	case "monochrome":
+		return MonochromeTheme, true
+	}
+	return Theme{}, false
+}
+
+// LoadTheme reads a theme file, which is a flat YAML (role: value) or TOML (role = "value") file.
+// Roles missing from the file use the colors of the default theme.
+func LoadTheme(path string) (Theme, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Theme{}, fmt.Errorf("error opening theme: %v", err)
+	}
+	defer file.Close()
+
+	theme := DefaultTheme
+	roles := map[string]*string{
+		"label":    &theme.Label,
+		"type":     &theme.Type,
+		"url":      &theme.URL,
+		"date":     &theme.Date,
+		"warning":  &theme.Warning,
+		"username": &theme.Username,
+	}
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" || strings.HasPrefix(line, "[") {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if eq := strings.Index(line, "="); eq >= 0 && (!found || eq < len(key)) {
+			key, value = line[:eq], line[eq+1:]
+		} else if !found {
+			return Theme{}, fmt.Errorf("invalid line %d in theme: %s", lineNumber, line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		role, ok := roles[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown role %q on line %d in theme", key, lineNumber)
+		}
+		if _, err := colorAttributes(value); err != nil {
+			return Theme{}, fmt.Errorf("invalid color on line %d in theme: %v", lineNumber, err)
+		}
+		*role = value
+	}
+	if err := scanner.Err(); err != nil {
+		return Theme{}, fmt.Errorf("error reading theme: %v", err)
+	}
+	return theme, nil
+}
+
+// colors returns the color functions for the roles of the theme
+func (t Theme) colors() Colors {
+	return Colors{
+		Label:    colorFunc(t.Label),
+		Type:     colorFunc(t.Type),
+		URL:      colorFunc(t.URL),
+		Date:     colorFunc(t.Date),
+		Warning:  colorFunc(t.Warning),
+		Username: colorFunc(t.Username),
+	}
+}
+
+// usesColors returns true if any role of the theme uses a color, instead of only text attributes
+func (t Theme) usesColors() bool {
+	for _, spec := range []string{t.Label, t.Type, t.URL, t.Date, t.Warning, t.Username} {
+		for _, name := range strings.Fields(strings.ToLower(spec)) {
+			if _, ok := textAttributes[name]; !ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// colorNames are the names of the foreground colors, bright (hi-) and background (bg-) variants are derived from them
+var colorNames = map[string]color.Attribute{
+	"black":   color.FgBlack,
+	"red":     color.FgRed,
+	"green":   color.FgGreen,
+	"yellow":  color.FgYellow,
+	"blue":    color.FgBlue,
+	"magenta": color.FgMagenta,
+	"cyan":    color.FgCyan,
+	"white":   color.FgWhite,
+}
+
+// textAttributes are the supported text attributes
+var textAttributes = map[string]color.Attribute{
+	"bold":      color.Bold,
+	"faint":     color.Faint,
+	"italic":    color.Italic,
+	"underline": color.Underline,
+	"blink":     color.BlinkSlow,
+	"reverse":   color.ReverseVideo,
+}
+
+// colorAttributes parses a space separated list of attributes and colors
+func colorAttributes(spec string) ([]color.Attribute, error) {
+	var attributes []color.Attribute
+	for _, name := range strings.Fields(strings.ToLower(spec)) {
+		if attribute, ok := textAttributes[name]; ok {
+			attributes = append(attributes, attribute)
+			continue
+		}
+		offset := color.Attribute(0)
+		colorName := name
+		if strings.HasPrefix(name, "hi-") {
+			offset, colorName = color.FgHiBlack-color.FgBlack, strings.TrimPrefix(name, "hi-")
+		} else if strings.HasPrefix(name, "bg-") {
+			offset, colorName = color.BgBlack-color.FgBlack, strings.TrimPrefix(name, "bg-")
+		}
+		attribute, ok := colorNames[colorName]
+		if !ok {
+			return nil, fmt.Errorf("unknown color or attribute: %s", name)
+		}
+		attributes = append(attributes, attribute+offset)
+	}
+	return attributes, nil
+}
+
+// colorFunc returns a function which colors its arguments according to the spec
+func colorFunc(spec string) func(a ...interface{}) string {
+	attributes, _ := colorAttributes(spec)
+	if len(attributes) == 0 {
+		return fmt.Sprint
+	}
+	return color.New(attributes...).SprintFunc()
+}
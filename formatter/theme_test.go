@@ -0,0 +1,42 @@
+package formatter
+
+import "testing"
+
+func TestStripANSIColors(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"plain", "plain"},
+		{"\x1b[1mbold\x1b[0m", "\x1b[1mbold\x1b[0m"},
+		{"\x1b[33myellow\x1b[0m", "yellow\x1b[0m"},
+		{"\x1b[1;4;33mbold\x1b[m", "\x1b[1;4mbold\x1b[m"},
+		{"\x1b[38;5;208morange", "orange"},
+		{"\x1b[3;38;2;255;128;0;48;5;17mitalic", "\x1b[3mitalic"},
+		{"\x1b[97;100mbright", "bright"},
+	}
+	for _, test := range tests {
+		if got := stripANSIColors(test.text); got != test.want {
+			t.Errorf("stripANSIColors(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestThemeUsesColors(t *testing.T) {
+	tests := []struct {
+		name  string
+		theme Theme
+		want  bool
+	}{
+		{"default", DefaultTheme, true},
+		{"high-contrast", HighContrastTheme, true},
+		{"monochrome", MonochromeTheme, false},
+		{"empty", Theme{}, false},
+		{"background color", Theme{Warning: "bold bg-red"}, true},
+	}
+	for _, test := range tests {
+		if got := test.theme.usesColors(); got != test.want {
+			t.Errorf("%s theme usesColors() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// truncate shortens the text to at most length characters, adding an ellipsis when it is cut.
+// Characters are grapheme clusters, so emojis and combined characters are never split.
+// A negative length means the text is never truncated.
+func truncate(text string, length int) string {
+	if length < 0 || uniseg.GraphemeClusterCount(text) <= length {
+		return text
+	}
+	keep := length - 3
+	if keep < 0 {
+		keep = length
+	}
+
+	var b strings.Builder
+	graphemes := uniseg.NewGraphemes(text)
+	for i := 0; i < keep && graphemes.Next(); i++ {
+		b.WriteString(graphemes.Str())
+	}
+	if keep == length {
+		return b.String()
+	}
+	return b.String() + "..."
+}
@@ -0,0 +1,31 @@
+package formatter
+
+import "testing"
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		length int
+		want   string
+	}{
+		{"short text", "Hello", 10, "Hello"},
+		{"exact length", "Hello", 5, "Hello"},
+		{"cut with ellipsis", "Hello world", 8, "Hello..."},
+		{"unlimited", "Hello world", -1, "Hello world"},
+		{"zero length", "Hello", 0, ""},
+		{"shorter than the ellipsis", "Hello", 2, "He"},
+		{"multibyte runes", "héllo wörld", 8, "héllo..."},
+		{"emoji with modifier", "👍🏽👍🏽👍🏽👍🏽👍🏽", 4, "👍🏽..."},
+		{"family emoji counts once", "👨‍👩‍👧‍👦ab", 3, "👨‍👩‍👧‍👦ab"},
+		{"combining characters", "ééééé", 4, "é..."},
+		{"flags", "🇳🇱🇩🇪🇫🇷🇧🇪🇬🇧", 4, "🇳🇱..."},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := truncate(test.text, test.length); got != test.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", test.text, test.length, got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package formatter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// isoDurationRegex matches ISO 8601 durations as used by ActivityStreams (e.g. PT1H2M3S)
+var isoDurationRegex = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// formatDuration formats an ISO 8601 duration into a more readable format (e.g. 1h02m)
+func formatDuration(isoDuration string) string {
+	matches := isoDurationRegex.FindStringSubmatch(isoDuration)
+	if matches == nil || isoDuration == "P" || isoDuration == "PT" {
+		return isoDuration
+	}
+	days, _ := strconv.Atoi(matches[1])
+	hours, _ := strconv.Atoi(matches[2])
+	minutes, _ := strconv.Atoi(matches[3])
+	seconds, _ := strconv.ParseFloat(matches[4], 64)
+
+	// Normalize, PeerTube for example only uses seconds (PT3723S)
+	total := days*86400 + hours*3600 + minutes*60 + int(seconds)
+	hours, minutes, secs := total/3600, total%3600/60, total%60
+
+	switch {
+	case hours > 0 && secs > 0:
+		return fmt.Sprintf("%dh%02dm%02ds", hours, minutes, secs)
+	case hours > 0:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%02ds", minutes, secs)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
+
+// formatBytes formats a size in bytes into a human-readable format
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// formatIdentifierName formats PeerTube style {identifier, name} objects, like category, licence and language
+func formatIdentifierName(value gjson.Result) string {
+	if !value.IsObject() {
+		return value.String()
+	}
+	name := value.Get("name").String()
+	identifier := value.Get("identifier").String()
+	if name == "" {
+		return identifier
+	}
+	if identifier == "" || identifier == name {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, identifier)
+}
+
+// formatVideoProperties formats the properties PeerTube adds to its Video objects
+func formatVideoProperties(jsonStr []byte, parts []string, bold, green, red func(a ...interface{}) string) []string {
+	if views := gjson.GetBytes(jsonStr, "views"); views.Exists() {
+		parts = append(parts, fmt.Sprintf("%s: %d", bold("Views"), views.Int()))
+	}
+
+	for _, property := range []struct{ path, label string }{
+		{"category", "Category"},
+		{"licence", "Licence"},
+		{"language", "Language"},
+	} {
+		if value := formatIdentifierName(gjson.GetBytes(jsonStr, property.path)); value != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold(property.label), value))
+		}
+	}
+
+	for _, property := range []struct{ path, label string }{
+		{"commentsEnabled", "Comments Enabled"},
+		{"downloadEnabled", "Download Enabled"},
+	} {
+		if value := gjson.GetBytes(jsonStr, property.path); value.Exists() {
+			valueStr := red("false")
+			if value.Bool() {
+				valueStr = green("true")
+			}
+			parts = append(parts, fmt.Sprintf("%s: %s", bold(property.label), valueStr))
+		}
+	}
+
+	if comments := firstString(gjson.GetBytes(jsonStr, "comments")); comments != "" {
+		parts = append(parts, fmt.Sprintf("%s: %s", bold("Comments"), green(comments)))
+	}
+
+	return parts
+}
+
+// formatLinks formats a list of Link objects, as used by PeerTube for its url property
+// (web page, HLS playlists, files per resolution, torrents and magnets)
+func formatLinks(links []gjson.Result, parts []string, bold, green func(a ...interface{}) string) []string {
+	parts = append(parts, fmt.Sprintf("%s:", bold("Links")))
+	for _, link := range links {
+		if !link.IsObject() {
+			parts = append(parts, fmt.Sprintf("  - %s", green(link.String())))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("  - %s", describeLink(link, green)))
+		// HLS playlists contain the files of every resolution as tags
+		for _, tag := range link.Get("tag").Array() {
+			if tag.Get("type").String() == "Link" && tag.Get("href").Exists() {
+				parts = append(parts, fmt.Sprintf("      - %s", describeLink(tag, green)))
+			}
+		}
+	}
+	return parts
+}
+
+// describeLink describes a single Link object, including its kind, resolution and size
+func describeLink(link gjson.Result, green func(a ...interface{}) string) string {
+	mediaType := link.Get("mediaType").String()
+	var kind string
+	switch {
+	case strings.Contains(mediaType, "x-scheme-handler/magnet"):
+		kind = "Magnet"
+	case strings.HasPrefix(mediaType, "application/x-bittorrent"):
+		kind = "Torrent"
+	case strings.EqualFold(mediaType, "application/x-mpegURL"):
+		kind = "HLS playlist"
+	case mediaType == "text/html":
+		kind = "Web page"
+	case mediaType != "":
+		kind = mediaType
+	default:
+		kind = strings.Join(resultStrings(link.Get("rel")), ", ")
+	}
+
+	var details []string
+	if height := link.Get("height").Int(); height > 0 {
+		details = append(details, fmt.Sprintf("%dp", height))
+	}
+	if fps := link.Get("fps").Int(); fps > 0 {
+		details = append(details, fmt.Sprintf("%d fps", fps))
+	}
+	if size := link.Get("size").Int(); size > 0 {
+		details = append(details, formatBytes(size))
+	}
+	if len(details) > 0 {
+		kind += fmt.Sprintf(" (%s)", strings.Join(details, ", "))
+	}
+	return fmt.Sprintf("%s: %s", kind, green(link.Get("href").String()))
+}
+
+// formatAttribution formats the attributedTo property, splitting PeerTube channels (Group) and accounts (Person)
+func formatAttribution(jsonStr []byte, parts []string, bold, green func(a ...interface{}) string) []string {
+	attributedTo := gjson.GetBytes(jsonStr, "attributedTo")
+	if attributedTo.Type == gjson.String {
+		return append(parts, fmt.Sprintf("%s: %s", bold("Author"), attributedTo.String()))
+	}
+	for _, attribution := range resultArray(attributedTo) {
+		label := "Author"
+		switch attribution.Get("type").String() {
+		case "Group":
+			label = "Channel"
+		case "Person":
+			label = "Account"
+		}
+		if id := firstString(attribution); id != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", bold(label), green(id)))
+		}
+	}
+	return parts
+}
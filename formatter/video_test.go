@@ -0,0 +1,30 @@
+package formatter
+
+import "testing"
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     string
+	}{
+		{"PT3723S", "1h02m03s"},
+		{"PT1H2M3S", "1h02m03s"},
+		{"PT1H", "1h00m"},
+		{"PT2M5S", "2m05s"},
+		{"PT45S", "45s"},
+		{"PT12.5S", "12s"},
+		{"P1DT1H", "25h00m"},
+		{"PT0S", "0s"},
+		// Invalid durations are returned as-is
+		{"P", "P"},
+		{"PT", "PT"},
+		{"1H", "1H"},
+		{"PT1.5H", "PT1.5H"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := formatDuration(test.duration); got != test.want {
+			t.Errorf("formatDuration(%q) = %q, want %q", test.duration, got, test.want)
+		}
+	}
+}
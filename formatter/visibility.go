@@ -0,0 +1,192 @@
+package formatter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Visibility levels, using the same names as Mastodon
+const (
+	VisibilityPublic    = "public"
+	VisibilityUnlisted  = "unlisted"
+	VisibilityFollowers = "followers-only"
+	VisibilityLimited   = "limited"
+	VisibilityDirect    = "direct"
+)
+
+// publicAddresses are all the ways the special public collection can be addressed
+var publicAddresses = []string{
+	"https://www.w3.org/ns/activitystreams#Public",
+	"as:Public",
+	"Public",
+}
+
+// isPublicAddress returns true if the recipient is the special public collection
+func isPublicAddress(recipient string) bool {
+	for _, public := range publicAddresses {
+		if recipient == public {
+			return true
+		}
+	}
+	return false
+}
+
+// isFollowersCollection returns true if the recipient looks like a followers collection,
+// either the one of the author or one ending on /followers (which is used by most software)
+func isFollowersCollection(recipient, authorFollowers string) bool {
+	if authorFollowers != "" && recipient == authorFollowers {
+		return true
+	}
+	return strings.HasSuffix(strings.TrimSuffix(recipient, "/"), "/followers")
+}
+
+// classifyVisibility derives the Mastodon-style visibility from the to/cc addressing of an object
+func classifyVisibility(jsonStr []byte) string {
+	to := resultStrings(gjson.GetBytes(jsonStr, "to"))
+	cc := resultStrings(gjson.GetBytes(jsonStr, "cc"))
+	if len(to) == 0 && len(cc) == 0 {
+		return ""
+	}
+
+	for _, recipient := range to {
+		if isPublicAddress(recipient) {
+			return VisibilityPublic
+		}
+	}
+	for _, recipient := range cc {
+		if isPublicAddress(recipient) {
+			return VisibilityUnlisted
+		}
+	}
+
+	authorFollowers := gjson.GetBytes(jsonStr, "attributedTo.followers").String()
+	recipients := append(to, cc...)
+	for _, recipient := range recipients {
+		if isFollowersCollection(recipient, authorFollowers) {
+			return VisibilityFollowers
+		}
+	}
+
+	// Only mentioned actors are addressed: it's a direct message. If anybody else is addressed
+	// (like a circle or a list collection), the post has a limited visibility.
+	mentioned := make(map[string]bool)
+	for _, tag := range resultArray(gjson.GetBytes(jsonStr, "tag")) {
+		if tag.Get("type").String() == "Mention" {
+			mentioned[tag.Get("href").String()] = true
+		}
+	}
+	for _, recipient := range recipients {
+		if !mentioned[recipient] {
+			return VisibilityLimited
+		}
+	}
+	return VisibilityDirect
+}
+
+// describeRecipients returns a readable description of every recipient, such as
+// "Public" or "followers of @user@domain". Recipients are only dereferenced when
+// the options allow it, otherwise the description is derived from the URL itself.
+func describeRecipients(recipients []string, opts Options, cache map[string]string) []string {
+	var descriptions []string
+	for _, recipient := range recipients {
+		if description, ok := cache[recipient]; ok {
+			descriptions = append(descriptions, description)
+			continue
+		}
+		description := describeRecipient(recipient, opts)
+		cache[recipient] = description
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+// describeRecipient returns a readable description of a single recipient
+func describeRecipient(recipient string, opts Options) string {
+	if isPublicAddress(recipient) {
+		return "Public"
+	}
+
+	if isFollowersCollection(recipient, "") {
+		owner := strings.TrimSuffix(strings.TrimSuffix(recipient, "/"), "/followers")
+		if opts.ResolveRecipients {
+			// The collection itself might tell us who the owner is (eg. attributedTo on Lemmy)
+			if collection := opts.fetch(recipient); collection != nil {
+				if attributedTo := gjson.GetBytes(collection, "attributedTo").String(); attributedTo != "" {
+					owner = attributedTo
+				}
+			}
+			if actor := opts.fetch(owner); actor != nil {
+				return "followers of " + actorHandle(actor, owner)
+			}
+		}
+		return "followers of " + owner
+	}
+
+	if opts.ResolveRecipients {
+		if object := opts.fetch(recipient); object != nil {
+			if gjson.GetBytes(object, "preferredUsername").Exists() {
+				return actorHandle(object, recipient)
+			}
+			if objectType := gjson.GetBytes(object, "type").String(); objectType != "" {
+				return fmt.Sprintf("%s (%s)", recipient, objectType)
+			}
+		}
+	}
+	return recipient
+}
+
+// actorHandle returns the @user@domain handle of an actor, including its display name when available
+func actorHandle(actor []byte, actorURL string) string {
+	username := gjson.GetBytes(actor, "preferredUsername").String()
+	if username == "" {
+		return actorURL
+	}
+	handle := "@" + username
+	if id := gjson.GetBytes(actor, "id").String(); id != "" {
+		actorURL = id
+	}
+	if host := urlHost(actorURL); host != "" {
+		handle += "@" + host
+	}
+	if name := gjson.GetBytes(actor, "name").String(); name != "" && name != username {
+		handle = fmt.Sprintf("%s (%s)", handle, name)
+	}
+	return handle
+}
+
+// urlHost returns the host of an URL, or an empty string if it isn't a valid URL
+func urlHost(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsedURL.Host
+}
+
+// resultStrings returns the string values of a result, which can either be a single value or an array
+func resultStrings(result gjson.Result) []string {
+	var values []string
+	if !result.Exists() {
+		return values
+	}
+	if result.IsArray() {
+		for _, value := range result.Array() {
+			if value.IsObject() {
+				values = append(values, value.Get("id").String())
+			} else if value.String() != "" {
+				values = append(values, value.String())
+			}
+		}
+		return values
+	}
+	if result.IsObject() {
+		return append(values, result.Get("id").String())
+	}
+	if result.String() != "" {
+		values = append(values, result.String())
+	}
+	return values
+}
@@ -0,0 +1,28 @@
+package formatter
+
+import "testing"
+
+func TestClassifyVisibility(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{"no addressing", `{"type":"Note"}`, ""},
+		{"public", `{"to":["https://www.w3.org/ns/activitystreams#Public"],"cc":["https://a.example/users/alice/followers"]}`, VisibilityPublic},
+		{"public as string", `{"to":"as:Public"}`, VisibilityPublic},
+		{"compacted public", `{"to":["Public"]}`, VisibilityPublic},
+		{"unlisted", `{"to":["https://a.example/users/alice/followers"],"cc":["https://www.w3.org/ns/activitystreams#Public"]}`, VisibilityUnlisted},
+		{"followers-only", `{"to":["https://a.example/users/alice/followers/"]}`, VisibilityFollowers},
+		{"followers of the embedded author", `{"attributedTo":{"followers":"https://a.example/fans"},"to":["https://a.example/fans"]}`, VisibilityFollowers},
+		{"direct", `{"to":["https://b.example/users/bob"],"tag":[{"type":"Mention","href":"https://b.example/users/bob"}]}`, VisibilityDirect},
+		{"limited", `{"to":["https://b.example/users/bob","https://a.example/circles/1"],"tag":[{"type":"Mention","href":"https://b.example/users/bob"}]}`, VisibilityLimited},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := classifyVisibility([]byte(test.json)); got != test.want {
+				t.Errorf("classifyVisibility(%s) = %q, want %q", test.json, got, test.want)
+			}
+		})
+	}
+}
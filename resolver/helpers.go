@@ -19,12 +19,16 @@ const (
 	// UserAgent is the user agent string used for all HTTP requests
 	UserAgent    = "FediResolve/1.0 (https://github.com/melroy89/FediResolve)"
 	AcceptHeader = "application/activity+json, application/ld+json"
+	// MaxImageSize is the maximum size of images downloaded for rendering in the terminal
+	MaxImageSize = 10 * 1024 * 1024
+	// MaxPageSize is the maximum size of web pages downloaded for verifying links
+	MaxPageSize = 1024 * 1024
 )
 
 // fetchActivityPubObjectWithSignature is a helper function that always signs HTTP requests
 // This is the preferred way to fetch ActivityPub content as many instances require signatures
 func (r *Resolver) fetchActivityPubObjectWithSignature(objectURL string) ([]byte, error) {
-	fmt.Printf("Fetching ActivityPub object with HTTP signatures from: %s\n", objectURL)
+	r.logf("Fetching ActivityPub object with HTTP signatures from: %s\n", objectURL)
 
 	// Fetch the object itself
 	data, err := r.fetchActivityPubObjectDirect(objectURL)
@@ -61,7 +65,7 @@ func (r *Resolver) fetchActivityPubObjectWithSignature(objectURL string) ([]byte
 	}
 
 	// Send the request
-	fmt.Printf("Sending signed request with headers: %v\n", req.Header)
+	r.logf("Sending signed request with headers: %v\n", req.Header)
 	resp, err := r.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error sending signed request: %v", err)
@@ -87,7 +91,7 @@ func (r *Resolver) fetchActivityPubObjectWithSignature(objectURL string) ([]byte
 // fetchActivityPubObjectDirect is a helper function to fetch content without signatures
 // This is used as a fallback when signing fails
 func (r *Resolver) fetchActivityPubObjectDirect(objectURL string) ([]byte, error) {
-	fmt.Printf("Fetching ActivityPub object directly from: %s\n", objectURL)
+	r.logf("Fetching ActivityPub object directly from: %s\n", objectURL)
 
 	// Create a custom client that doesn't follow redirects automatically
 	// so we can capture the redirect URL
@@ -108,14 +112,14 @@ func (r *Resolver) fetchActivityPubObjectDirect(objectURL string) ([]byte, error
 	req.Header.Set("User-Agent", UserAgent)
 
 	// Perform the request
-	fmt.Printf("Sending direct request with headers: %v\n", req.Header)
+	r.logf("Sending direct request with headers: %v\n", req.Header)
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching content: %v", err)
 	}
 	defer resp.Body.Close()
 
-	fmt.Printf("Received response with status: %s\n", resp.Status)
+	r.logf("Received response with status: %s\n", resp.Status)
 
 	// Check if we got a redirect (302, 301, etc.)
 	if resp.StatusCode == http.StatusFound || resp.StatusCode == http.StatusMovedPermanently ||
@@ -123,7 +127,7 @@ func (r *Resolver) fetchActivityPubObjectDirect(objectURL string) ([]byte, error
 		// Get the redirect URL from the Location header
 		redirectURL := resp.Header.Get("Location")
 		if redirectURL != "" {
-			fmt.Printf("Found redirect to: %s\n", redirectURL)
+			r.logf("Found redirect to: %s\n", redirectURL)
 			// Try to fetch the content from the redirect URL with HTTP signatures
 			return r.fetchActivityPubObjectWithSignature(redirectURL)
 		}
@@ -142,7 +146,7 @@ func (r *Resolver) fetchActivityPubObjectDirect(objectURL string) ([]byte, error
 	}
 
 	// Debug output
-	fmt.Printf("Response content type: %s\n", resp.Header.Get("Content-Type"))
+	r.logf("Response content type: %s\n", resp.Header.Get("Content-Type"))
 
 	// Check if the response is empty
 	if len(bodyBytes) == 0 {
@@ -154,7 +158,7 @@ func (r *Resolver) fetchActivityPubObjectDirect(objectURL string) ([]byte, error
 
 // fetchActorData fetches actor data from an actor URL
 func (r *Resolver) fetchActorData(actorURL string) ([]byte, error) {
-	fmt.Printf("Fetching actor data from: %s\n", actorURL)
+	r.logf("Fetching actor data from: %s\n", actorURL)
 
 	// Create the request
 	req, err := http.NewRequest("GET", actorURL, nil)
@@ -186,6 +190,67 @@ func (r *Resolver) fetchActorData(actorURL string) ([]byte, error) {
 	return bodyBytes, nil
 }
 
+// fetchImage downloads an image, refusing images larger than MaxImageSize
+func (r *Resolver) fetchImage(imageURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating image request: %v", err)
+	}
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching image: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image request failed with status: %s", resp.Status)
+	}
+	if resp.ContentLength > MaxImageSize {
+		return nil, fmt.Errorf("image is too large: %d bytes", resp.ContentLength)
+	}
+
+	// The content length can't be trusted, so limit the read as well
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, MaxImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading image: %v", err)
+	}
+	if len(bodyBytes) > MaxImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", MaxImageSize)
+	}
+	return bodyBytes, nil
+}
+
+// fetchPage downloads a web page, only the first MaxPageSize bytes are returned
+func (r *Resolver) fetchPage(pageURL string) ([]byte, error) {
+	r.logf("Fetching web page from: %s\n", pageURL)
+
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating page request: %v", err)
+	}
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("page request failed with status: %s", resp.Status)
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(resp.Body, MaxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("error reading page: %v", err)
+	}
+	return bodyBytes, nil
+}
+
 // extractPublicKey extracts the public key ID from actor data
 // TODO: We are actually now extracting the ID not the public key pem....
 // Lets  see if we can improve this, without breaking the signing.
@@ -194,7 +259,7 @@ func (r *Resolver) extractPublicKey(data []byte) (string, error) {
 	actorURL := gjson.GetBytes(data, "attributedTo").String()
 
 	if actorURL == "" {
-		fmt.Printf("Could not find attributedTo in object\n")
+		r.logf("Could not find attributedTo in object\n")
 		// Try to find key in the object itself
 		keyID := gjson.GetBytes(data, "publicKey.id").String()
 
@@ -215,7 +280,7 @@ func (r *Resolver) extractPublicKey(data []byte) (string, error) {
 			keyID = gjson.GetBytes(actorData, "publicKey.0.id").String()
 		}
 		if keyID == "" {
-			fmt.Printf("could not find public key ID in actor data")
+			r.logf("could not find public key ID in actor data")
 			return "dummy", nil
 		}
 		return keyID, nil
@@ -261,7 +326,7 @@ func signRequest(req *http.Request, keyID string, privateKey *rsa.PrivateKey) er
 // fetchNodeInfo fetches nodeinfo from the given domain, returning the raw JSON
 func (r *Resolver) fetchNodeInfo(domain string) ([]byte, error) {
 	nodeinfoURL := "https://" + domain + "/.well-known/nodeinfo"
-	fmt.Printf("Fetching nodeinfo discovery from: %s\n", nodeinfoURL)
+	r.logf("Fetching nodeinfo discovery from: %s\n", nodeinfoURL)
 
 	resp, err := r.client.Get(nodeinfoURL)
 	if err != nil {
@@ -288,7 +353,7 @@ func (r *Resolver) fetchNodeInfo(domain string) ([]byte, error) {
 		return nil, fmt.Errorf("no nodeinfo schema 2.1 or 2.0 found")
 	}
 
-	fmt.Printf("Fetching nodeinfo from: %s\n", nodeinfoHref)
+	r.logf("Fetching nodeinfo from: %s\n", nodeinfoHref)
 	resp2, err := r.client.Get(nodeinfoHref)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching nodeinfo: %v", err)
@@ -319,7 +384,50 @@ func (r *Resolver) ResolveObjectOrNodeInfo(objectURL string) ([]byte, error) {
 	return body, nil
 }
 
-// Format result
-func formatResult(raw []byte) (string, error) {
-	return formatter.Format(raw)
+// FormatResult formats the raw JSON of a resolved object, linked objects are dereferenced
+// using the resolver when the options ask for it
+func (r *Resolver) FormatResult(raw []byte) (string, error) {
+	return formatter.FormatWithOptions(raw, r.formatOptions())
+}
+
+// Summarize returns the summary of a resolved object, without the JSON
+func (r *Resolver) Summarize(raw []byte) string {
+	return formatter.Summarize(raw, r.formatOptions())
+}
+
+// Export exports the raw JSON of a resolved object as markdown or HTML document
+func (r *Resolver) Export(raw []byte, format string) (string, error) {
+	switch format {
+	case formatter.OutputFormatMarkdown:
+		return formatter.FormatMarkdown(raw, r.formatOptions())
+	case formatter.OutputFormatHTML:
+		return formatter.FormatHTML(raw, r.formatOptions())
+	}
+	return "", fmt.Errorf("unsupported export format: %s", format)
+}
+
+// ExportThread exports the posts of a thread (oldest first) as a single document in the given format (markdown or HTML)
+func (r *Resolver) ExportThread(posts [][]byte, format string) (string, error) {
+	switch format {
+	case formatter.OutputFormatMarkdown:
+		return formatter.FormatMarkdownThread(posts, r.formatOptions())
+	case formatter.OutputFormatHTML:
+		return formatter.FormatHTMLThread(posts, r.formatOptions())
+	}
+	return "", fmt.Errorf("unsupported export format: %s", format)
+}
+
+// formatOptions returns the format options, using the resolver to fetch linked objects, images and pages
+func (r *Resolver) formatOptions() formatter.Options {
+	opts := r.FormatOptions
+	if opts.Fetch == nil {
+		opts.Fetch = r.fetchActivityPubObjectRaw
+	}
+	if opts.FetchImage == nil {
+		opts.FetchImage = r.fetchImage
+	}
+	if opts.FetchPage == nil {
+		opts.FetchPage = r.fetchPage
+	}
+	return opts
 }
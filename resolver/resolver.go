@@ -6,13 +6,20 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 	"time"
+
+	"gitlab.melroy.org/melroy/fediresolve/formatter"
 )
 
 // Resolver handles the resolution of Fediverse URLs and handles
 type Resolver struct {
 	client *http.Client
+	// FormatOptions are passed to the formatter when formatting the result
+	FormatOptions formatter.Options
+	// Log receives the progress messages, when nil they are written to stdout
+	Log io.Writer
 }
 
 // NewResolver creates a new Resolver instance
@@ -24,6 +31,24 @@ func NewResolver() *Resolver {
 	}
 }
 
+// logf writes a progress message to the log
+func (r *Resolver) logf(format string, a ...interface{}) {
+	fmt.Fprintf(r.logWriter(), format, a...)
+}
+
+// logln writes a progress message, followed by a newline, to the log
+func (r *Resolver) logln(a ...interface{}) {
+	fmt.Fprintln(r.logWriter(), a...)
+}
+
+// logWriter returns the writer progress messages are written to
+func (r *Resolver) logWriter() io.Writer {
+	if r.Log == nil {
+		return os.Stdout
+	}
+	return r.Log
+}
+
 // ResolveInput is a convenience function that creates a new resolver and resolves the input
 func ResolveInput(input string) (string, error) {
 	r := NewResolver()
@@ -32,6 +57,19 @@ func ResolveInput(input string) (string, error) {
 
 // Resolve takes a URL or handle and resolves it to a formatted result
 func (r *Resolver) Resolve(input string) (string, error) {
+	raw, err := r.ResolveRaw(input)
+	if err != nil {
+		return "", err
+	}
+	formatted, err := r.FormatResult(raw)
+	if err != nil {
+		return "", fmt.Errorf("error formatting result: %v", err)
+	}
+	return formatted, nil
+}
+
+// ResolveRaw takes a URL or handle and resolves it to the raw JSON (ActivityPub object or nodeinfo)
+func (r *Resolver) ResolveRaw(input string) ([]byte, error) {
 	// Always prepend https:// if missing and not a handle
 	inputNorm := input
 	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") && !strings.Contains(input, "@") {
@@ -43,18 +81,14 @@ func (r *Resolver) Resolve(input string) (string, error) {
 		// Looks like a root domain (with or without scheme), fetch nodeinfo
 		raw, err := r.ResolveObjectOrNodeInfo(parsedURL.String())
 		if err != nil {
-			return "", fmt.Errorf("error fetching nodeinfo: %v", err)
-		}
-		formatted, err := formatResult(raw)
-		if err != nil {
-			return "", fmt.Errorf("error formatting nodeinfo: %v", err)
+			return nil, fmt.Errorf("error fetching nodeinfo: %v", err)
 		}
-		return formatted, nil
+		return raw, nil
 	}
 
 	// If not a root domain, proceed with other checks
 	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
-		fmt.Println("Detected URL, attempting direct resolution")
+		r.logln("Detected URL, attempting direct resolution")
 		return r.resolveURL(input)
 	}
 
@@ -62,22 +96,56 @@ func (r *Resolver) Resolve(input string) (string, error) {
 		if !strings.Contains(input, "/") && !strings.Contains(input, ":") {
 			if strings.HasPrefix(input, "@") {
 				if strings.Count(input, "@") == 2 {
-					fmt.Println("Detected Fediverse handle, using WebFinger resolution")
+					r.logln("Detected Fediverse handle, using WebFinger resolution")
 					return r.resolveHandle(input)
 				}
 			} else {
 				if strings.Count(input, "@") == 1 {
-					fmt.Println("Detected Fediverse handle, using WebFinger resolution")
+					r.logln("Detected Fediverse handle, using WebFinger resolution")
 					return r.resolveHandle(input)
 				}
 			}
 		}
 	}
 
-	fmt.Println("Input format unclear, attempting URL resolution")
+	r.logln("Input format unclear, attempting URL resolution")
+	return r.resolveURL(input)
+}
+
+// ResolveActor resolves the input as actor, without the heuristics of ResolveRaw. The input is a
+// handle, an URL, or a bare domain (which resolves to the instance actor, like @domain@domain).
+func (r *Resolver) ResolveActor(input string) ([]byte, error) {
+	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+		return r.resolveURL(input)
+	}
+	if strings.Contains(input, "@") {
+		return r.resolveHandle(input)
+	}
+	domain := strings.TrimSuffix(input, "/")
+	return r.resolveHandle(domain + "@" + domain)
+}
+
+// ResolveObject resolves the input as URL of an ActivityPub object, without the heuristics of ResolveRaw
+func (r *Resolver) ResolveObject(input string) ([]byte, error) {
+	if !strings.HasPrefix(input, "http://") && !strings.HasPrefix(input, "https://") {
+		input = "https://" + input
+	}
 	return r.resolveURL(input)
 }
 
+// NodeInfo fetches the nodeinfo of the instance, the input is a domain or an URL on the instance
+func (r *Resolver) NodeInfo(input string) ([]byte, error) {
+	domain := input
+	if parsedURL, err := url.Parse(input); err == nil && parsedURL.Host != "" {
+		domain = parsedURL.Host
+	}
+	domain = strings.TrimSuffix(domain, "/")
+	if domain == "" {
+		return nil, fmt.Errorf("invalid domain: %s", input)
+	}
+	return r.fetchNodeInfo(domain)
+}
+
 // WebFingerResponse represents the structure of a WebFinger response
 type WebFingerResponse struct {
 	Subject string `json:"subject"`
@@ -88,17 +156,16 @@ type WebFingerResponse struct {
 	} `json:"links"`
 }
 
-// resolveHandle resolves a Fediverse handle using WebFinger
-func (r *Resolver) resolveHandle(handle string) (string, error) {
+// WebFinger looks up a Fediverse handle (@user@domain.tld) using WebFinger, returning the raw JRD
+func (r *Resolver) WebFinger(handle string) ([]byte, error) {
 	// Remove @ prefix if present
-	if handle[0] == '@' {
-		handle = handle[1:]
-	}
+	handle = strings.TrimPrefix(handle, "@")
 
 	// Split handle into username and domain
+	// Profile URLs and acct: URIs would result in a bogus username or domain
 	parts := strings.Split(handle, "@")
-	if len(parts) != 2 {
-		return "", fmt.Errorf("invalid handle format: %s", handle)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" || strings.ContainsAny(parts[0], ":/") || strings.Contains(parts[1], "/") {
+		return nil, fmt.Errorf("invalid handle format: %s (expected user@domain)", handle)
 	}
 
 	username, domain := parts[0], parts[1]
@@ -108,12 +175,12 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 	webfingerURL := fmt.Sprintf("https://%s/.well-known/webfinger?resource=%s",
 		domain, url.QueryEscape(resource))
 
-	fmt.Printf("Fetching WebFinger data from: %s\n", webfingerURL)
+	r.logf("Fetching WebFinger data from: %s\n", webfingerURL)
 
 	// Create request for WebFinger data
 	req, err := http.NewRequest("GET", webfingerURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("error creating WebFinger request: %v", err)
+		return nil, fmt.Errorf("error creating WebFinger request: %v", err)
 	}
 
 	// Set appropriate headers for WebFinger
@@ -123,26 +190,35 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 	// Fetch WebFinger data
 	resp, err := r.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("error fetching WebFinger data: %v", err)
+		return nil, fmt.Errorf("error fetching WebFinger data: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("WebFinger request failed with status: %s", resp.Status)
+		return nil, fmt.Errorf("WebFinger request failed with status: %s", resp.Status)
 	}
 
-	// Read and parse the WebFinger response
+	// Read the WebFinger response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("error reading WebFinger response: %v", err)
+		return nil, fmt.Errorf("error reading WebFinger response: %v", err)
 	}
 
-	fmt.Printf("WebFinger response content type: %s\n", resp.Header.Get("Content-Type"))
-	fmt.Printf("WebFinger response body: %s\n", string(body))
+	r.logf("WebFinger response content type: %s\n", resp.Header.Get("Content-Type"))
+	r.logf("WebFinger response body: %s\n", string(body))
+	return body, nil
+}
+
+// resolveHandle resolves a Fediverse handle using WebFinger
+func (r *Resolver) resolveHandle(handle string) ([]byte, error) {
+	body, err := r.WebFinger(handle)
+	if err != nil {
+		return nil, err
+	}
 
 	var webfinger WebFingerResponse
 	if err := json.Unmarshal(body, &webfinger); err != nil {
-		return "", fmt.Errorf("error decoding WebFinger response: %v", err)
+		return nil, fmt.Errorf("error decoding WebFinger response: %v", err)
 	}
 
 	// Find the ActivityPub actor URL
@@ -152,7 +228,7 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 	for _, link := range webfinger.Links {
 		if link.Rel == "self" && strings.Contains(link.Type, "activity+json") {
 			actorURL = link.Href
-			fmt.Printf("Found ActivityPub actor URL with type %s: %s\n", link.Type, actorURL)
+			r.logf("Found ActivityPub actor URL with type %s: %s\n", link.Type, actorURL)
 			break
 		}
 	}
@@ -162,7 +238,7 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 		for _, link := range webfinger.Links {
 			if link.Rel == "self" {
 				actorURL = link.Href
-				fmt.Printf("Found ActivityPub actor URL with rel=self: %s\n", actorURL)
+				r.logf("Found ActivityPub actor URL with rel=self: %s\n", actorURL)
 				break
 			}
 		}
@@ -173,14 +249,14 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 		for _, link := range webfinger.Links {
 			if link.Rel == "http://webfinger.net/rel/profile-page" {
 				actorURL = link.Href
-				fmt.Printf("Using profile page as fallback: %s\n", actorURL)
+				r.logf("Using profile page as fallback: %s\n", actorURL)
 				break
 			}
 		}
 	}
 
 	if actorURL == "" {
-		return "", fmt.Errorf("could not find any suitable URL in WebFinger response")
+		return nil, fmt.Errorf("could not find any suitable URL in WebFinger response")
 	}
 
 	// Now fetch the actor data
@@ -188,7 +264,7 @@ func (r *Resolver) resolveHandle(handle string) (string, error) {
 }
 
 // resolveURL resolves a Fediverse URL to its ActivityPub representation
-func (r *Resolver) resolveURL(inputURL string) (string, error) {
+func (r *Resolver) resolveURL(inputURL string) ([]byte, error) {
 	// Always fetch the provided URL as-is, using ActivityPub Accept header and HTTP signatures
 	// Then, if the response contains an `id` field that differs from the requested URL, fetch that recursively
 	return r.resolveCanonicalActivityPub(inputURL, 0)
@@ -196,35 +272,31 @@ func (r *Resolver) resolveURL(inputURL string) (string, error) {
 
 // resolveCanonicalActivityPub fetches the ActivityPub object at the given URL, and if the response contains an `id` field
 // that differs from the requested URL, recursively fetches that canonical URL. Max depth is used to prevent infinite loops.
-func (r *Resolver) resolveCanonicalActivityPub(objectURL string, depth int) (string, error) {
+func (r *Resolver) resolveCanonicalActivityPub(objectURL string, depth int) ([]byte, error) {
 	if depth > 3 {
-		return "", fmt.Errorf("too many canonical redirects (possible loop)")
+		return nil, fmt.Errorf("too many canonical redirects (possible loop)")
 	}
-	fmt.Printf("Fetching ActivityPub object for canonical resolution: %s\n", objectURL)
+	r.logf("Fetching ActivityPub object for canonical resolution: %s\n", objectURL)
 	raw, err := r.fetchActivityPubObjectRaw(objectURL)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	var data map[string]interface{}
 	if err := json.Unmarshal(raw, &data); err != nil {
-		return "", fmt.Errorf("error parsing ActivityPub JSON: %v", err)
+		return nil, fmt.Errorf("error parsing ActivityPub JSON: %v", err)
 	}
 	idVal, ok := data["id"].(string)
 	if ok && idVal != "" && idVal != objectURL {
-		fmt.Printf("Found canonical id: %s (different from requested URL), following...\n", idVal)
+		r.logf("Found canonical id: %s (different from requested URL), following...\n", idVal)
 		return r.resolveCanonicalActivityPub(idVal, depth+1)
 	}
-	// If no id or already canonical, format and return using helpers.go
-	formatted, err := formatResult(raw)
-	if err != nil {
-		return "", fmt.Errorf("error formatting ActivityPub object: %v", err)
-	}
-	return formatted, nil
+	// If no id or already canonical, return the object
+	return raw, nil
 }
 
 // fetchActivityPubObjectRaw fetches an ActivityPub object and returns the raw JSON []byte (not formatted)
 func (r *Resolver) fetchActivityPubObjectRaw(objectURL string) ([]byte, error) {
-	fmt.Printf("Fetching ActivityPub object with HTTP from: %s\n", objectURL)
+	r.logf("Fetching ActivityPub object with HTTP from: %s\n", objectURL)
 
 	req, err := http.NewRequest("GET", objectURL, nil)
 	if err != nil {
@@ -254,13 +326,13 @@ func (r *Resolver) fetchActivityPubObjectRaw(objectURL string) ([]byte, error) {
 
 // fetchActivityPubObject fetches an ActivityPub object from a URL
 // This function now uses a signature-first approach by default
-func (r *Resolver) fetchActivityPubObject(objectURL string) (string, error) {
-	fmt.Printf("Fetching ActivityPub object from: %s\n", objectURL)
+func (r *Resolver) fetchActivityPubObject(objectURL string) ([]byte, error) {
+	r.logf("Fetching ActivityPub object from: %s\n", objectURL)
 
 	// Make sure the URL is valid
 	parsedURL, err := url.Parse(objectURL)
 	if err != nil {
-		return "", fmt.Errorf("invalid URL: %v", err)
+		return nil, fmt.Errorf("invalid URL: %v", err)
 	}
 
 	// Ensure the URL has a scheme
@@ -271,11 +343,7 @@ func (r *Resolver) fetchActivityPubObject(objectURL string) (string, error) {
 	// Use our signature-first approach by default
 	raw, err := r.fetchActivityPubObjectWithSignature(objectURL)
 	if err != nil {
-		return "", fmt.Errorf("error fetching ActivityPub object: %v", err)
+		return nil, fmt.Errorf("error fetching ActivityPub object: %v", err)
 	}
-	formatted, err := formatResult(raw)
-	if err != nil {
-		return "", fmt.Errorf("error formatting ActivityPub object: %v", err)
-	}
-	return formatted, nil
+	return raw, nil
 }